@@ -0,0 +1,46 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package conv
+
+import (
+	"encoding/base64"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// Base64Bytes is the bytes which is converted from and to the standard base64 string, e.g. the key
+// or certificate in properties. The plain []byte is converted as the slice of uint8 by its kind,
+// so the field must be declared as Base64Bytes to read the base64 value.
+type Base64Bytes []byte
+
+// UnmarshalText implement the encoding.TextUnmarshaler.
+func (b *Base64Bytes) UnmarshalText(text []byte) error {
+	ret, err := base64.StdEncoding.DecodeString(string(text))
+	if err != nil {
+		return xerrors.Wrapf(err, "it must be base64 encoded")
+	}
+	*b = ret
+	return nil
+}
+
+// MarshalText implement the encoding.TextMarshaler.
+func (b Base64Bytes) MarshalText() ([]byte, error) {
+	return []byte(base64.StdEncoding.EncodeToString(b)), nil
+}
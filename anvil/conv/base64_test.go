@@ -0,0 +1,42 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package conv
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestBase64Bytes(t *testing.T) {
+	g := NewWithT(t)
+
+	text, err := Base64Bytes("hello").MarshalText()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(text)).To(Equal("aGVsbG8="))
+
+	var b Base64Bytes
+	g.Expect(b.UnmarshalText(text)).To(Succeed())
+	g.Expect(b).To(Equal(Base64Bytes("hello")))
+
+	err = b.UnmarshalText([]byte("hello"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("it must be base64 encoded"))
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package conv
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// ByteSize is the size in bytes, which can be parsed from the string with unit, e.g. '10MiB', '1.5GB'.
+type ByteSize int64
+
+// The units of ByteSize, the decimal units are power of 1000 and the binary units are power of 1024.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+)
+
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"k":   KiB,
+	"kb":  KB,
+	"kib": KiB,
+	"m":   MiB,
+	"mb":  MB,
+	"mib": MiB,
+	"g":   GiB,
+	"gb":  GB,
+	"gib": GiB,
+	"t":   TiB,
+	"tb":  TB,
+	"tib": TiB,
+}
+
+// ParseByteSize parse the size with optional unit, e.g. '512', '10MiB', '1.5GB'. The unit is
+// case-insensitive, and the single letter unit (e.g. 'M') is binary unit.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok || i == 0 {
+		return 0, xerrors.Errorf("Cann't convert %s to type conv.ByteSize, it must format as number with unit, e.g. 10MiB", s)
+	}
+
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, xerrors.Wrapf(err, "Cann't convert %s to type conv.ByteSize", s)
+	}
+	return ByteSize(n * float64(unit)), nil
+}
+
+// String return the size with the largest binary unit which can represent it exactly, e.g. '10MiB'.
+func (s ByteSize) String() string {
+	for _, u := range []struct {
+		name string
+		size ByteSize
+	}{
+		{"TiB", TiB},
+		{"GiB", GiB},
+		{"MiB", MiB},
+		{"KiB", KiB},
+	} {
+		if s != 0 && s%u.size == 0 {
+			return strconv.FormatInt(int64(s/u.size), 10) + u.name
+		}
+	}
+	return strconv.FormatInt(int64(s), 10) + "B"
+}
+
+// UnmarshalText implement the encoding.TextUnmarshaler.
+func (s *ByteSize) UnmarshalText(text []byte) error {
+	v, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implement the encoding.TextMarshaler.
+func (s ByteSize) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package conv
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseByteSize(t *testing.T) {
+	type testCase struct {
+		desp   string
+		s      string
+		expect ByteSize
+		err    string
+	}
+	testCases := []testCase{
+		{desp: "bytes", s: "512", expect: 512},
+		{desp: "bytes with unit", s: "512B", expect: 512},
+		{desp: "binary unit", s: "10MiB", expect: 10 * MiB},
+		{desp: "decimal unit", s: "10MB", expect: 10 * MB},
+		{desp: "single letter unit", s: "2g", expect: 2 * GiB},
+		{desp: "fraction", s: "1.5KiB", expect: 1536},
+		{desp: "space between unit", s: " 3 TiB ", expect: 3 * TiB},
+		{desp: "unknown unit", s: "10XB", err: "Cann't convert 10XB to type conv.ByteSize"},
+		{desp: "no number", s: "MiB", err: "Cann't convert MiB to type conv.ByteSize"},
+		{desp: "invalid number", s: "1.2.3M", err: "Cann't convert 1.2.3M to type conv.ByteSize"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			actual, err := ParseByteSize(tc.s)
+			if tc.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(actual).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestByteSizeString(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(ByteSize(0).String()).To(Equal("0B"))
+	g.Expect(ByteSize(1000).String()).To(Equal("1000B"))
+	g.Expect((2 * KiB).String()).To(Equal("2KiB"))
+	g.Expect((1536 * MiB).String()).To(Equal("1536MiB"))
+	g.Expect((3 * TiB).String()).To(Equal("3TiB"))
+}
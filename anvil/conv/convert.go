@@ -24,6 +24,7 @@ import (
 	"context"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/anyvoxel/airmid/anvil/xerrors"
@@ -169,17 +170,68 @@ func ConvertToTimeDuration(_ context.Context, data []string) (any, error) {
 	return time.ParseDuration(data[0])
 }
 
-// ConvertTo return data to typ.
-//
-//nolint:revive,exhaustive,cyclop
+// ConvertToMap converts []string to map typ, every element must be formatted as 'key=value'.
+func ConvertToMap(ctx context.Context, typ reflect.Type, data []string) (any, error) {
+	ret := reflect.MakeMapWithSize(typ, len(data))
+	for _, v := range data {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			return nil, xerrors.Errorf("Cann't convert %s to type %s, it must format as key=value", v, typ.String())
+		}
+
+		k, err := ConvertTo(ctx, typ.Key(), kv[:1])
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := ConvertTo(ctx, typ.Elem(), kv[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		ret.SetMapIndex(reflect.ValueOf(k).Convert(typ.Key()), reflect.ValueOf(e).Convert(typ.Elem()))
+	}
+	return ret.Interface(), nil
+}
+
+// ConvertTo return data to typ, the function registered by Register and the encoding.TextUnmarshaler
+// are consulted first. The returned value is always the exact typ, e.g. the named type
+// (type Mode string), pointer (*int) and fixed-size array ([2]int) are supported.
 func ConvertTo(ctx context.Context, typ reflect.Type, data []string) (any, error) {
 	// NOTE: we must check the typ first, otherwise if the type is time.Duration
 	// the kind will be reflect.Int64
-	if typ == reflect.TypeOf(time.Duration(0)) {
-		return ConvertToTimeDuration(ctx, data)
+	if fn, ok := lookupConverter(typ); ok {
+		if len(data) == 0 {
+			return nil, xerrors.Errorf("Cann't convert empty data to type %s", typ.String())
+		}
+		return fn(ctx, data)
+	}
+
+	v, err := convertToKind(ctx, typ, data)
+	if err != nil {
+		return nil, err
 	}
 
+	// The value of named type is converted as its underlying type, e.g. 'type Mode string'
+	rv := reflect.ValueOf(v)
+	if rv.Type() != typ {
+		rv = rv.Convert(typ)
+	}
+	return rv.Interface(), nil
+}
+
+//nolint:revive,exhaustive,cyclop
+func convertToKind(ctx context.Context, typ reflect.Type, data []string) (any, error) {
 	switch typ.Kind() {
+	case reflect.Ptr:
+		e, err := ConvertTo(ctx, typ.Elem(), data)
+		if err != nil {
+			return nil, err
+		}
+
+		ret := reflect.New(typ.Elem())
+		ret.Elem().Set(reflect.ValueOf(e))
+		return ret.Interface(), nil
 	case reflect.Slice:
 		ret := reflect.MakeSlice(typ, 0, len(data))
 		for _, v := range data {
@@ -191,6 +243,23 @@ func ConvertTo(ctx context.Context, typ reflect.Type, data []string) (any, error
 			ret = reflect.Append(ret, reflect.ValueOf(i))
 		}
 		return ret.Interface(), nil
+	case reflect.Array:
+		if len(data) > typ.Len() {
+			return nil, xerrors.Errorf("Cann't convert %v elements to type %s", len(data), typ.String())
+		}
+
+		ret := reflect.New(typ).Elem()
+		for idx, v := range data {
+			i, err := ConvertTo(ctx, typ.Elem(), []string{v})
+			if err != nil {
+				return nil, err
+			}
+
+			ret.Index(idx).Set(reflect.ValueOf(i))
+		}
+		return ret.Interface(), nil
+	case reflect.Map:
+		return ConvertToMap(ctx, typ, data)
 	case reflect.Bool:
 		return ConvertToBool(ctx, data)
 	case reflect.Int:
@@ -224,10 +293,15 @@ func ConvertTo(ctx context.Context, typ reflect.Type, data []string) (any, error
 	return nil, xerrors.Errorf("Unsupport target type %s", typ.String())
 }
 
-// ToString convert i to string.
+// ToString convert i to string, the function registered by RegisterToString and the
+// encoding.TextMarshaler are consulted first.
 //
 //nolint:revive,cyclop
 func ToString(i any) (string, error) {
+	if fn, ok := lookupToString(i); ok {
+		return fn(i)
+	}
+
 	switch s := i.(type) {
 	case string:
 		return s, nil
@@ -257,8 +331,34 @@ func ToString(i any) (string, error) {
 		return strconv.FormatUint(uint64(s), 10), nil
 	case uint8:
 		return strconv.FormatUint(uint64(s), 10), nil
-	case time.Duration:
-		return s.String(), nil
+	}
+
+	return kindToString(i)
+}
+
+// kindToString convert i to string by its kind, e.g. the named type (type Mode string) and pointer.
+//
+//nolint:exhaustive
+func kindToString(i any) (string, error) {
+	v := reflect.ValueOf(i)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "", xerrors.Errorf("Cann't convert nil pointer '%T' to string", i)
+		}
+		return ToString(v.Elem().Interface())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	case reflect.String:
+		return v.String(), nil
 	}
 
 	return "", xerrors.Errorf("Unsupport target type '%T'", i)
@@ -28,6 +28,8 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+type testNamedString string
+
 func TestConvertToBool(t *testing.T) {
 	type testCase struct {
 		desp   string
@@ -720,6 +722,46 @@ func TestConvertTo(t *testing.T) {
 			expect: []int{},
 			err:    "Cann't convert true to type int",
 		},
+		{
+			desp: "normal map[string]int",
+			data: []string{"a=1", "b=2"},
+			typ:  reflect.TypeOf(map[string]int{}),
+			expect: map[string]int{
+				"a": 1,
+				"b": 2,
+			},
+			err: "",
+		},
+		{
+			desp: "normal map[int]string with '=' in value",
+			data: []string{"1=a=b"},
+			typ:  reflect.TypeOf(map[int]string{}),
+			expect: map[int]string{
+				1: "a=b",
+			},
+			err: "",
+		},
+		{
+			desp:   "map without '='",
+			data:   []string{"a"},
+			typ:    reflect.TypeOf(map[string]int{}),
+			expect: nil,
+			err:    "Cann't convert a to type map\\[string\\]int, it must format as key=value",
+		},
+		{
+			desp:   "map key failed",
+			data:   []string{"a=1"},
+			typ:    reflect.TypeOf(map[int]int{}),
+			expect: nil,
+			err:    "Cann't convert a to type int",
+		},
+		{
+			desp:   "map value failed",
+			data:   []string{"1=a"},
+			typ:    reflect.TypeOf(map[int]int{}),
+			expect: nil,
+			err:    "Cann't convert a to type int",
+		},
 		{
 			desp:   "unsupport type",
 			data:   []string{"true"},
@@ -727,6 +769,62 @@ func TestConvertTo(t *testing.T) {
 			expect: []int{},
 			err:    "Unsupport target type testing.T",
 		},
+		{
+			desp:   "named type",
+			data:   []string{"a"},
+			typ:    reflect.TypeOf(testNamedString("")),
+			expect: testNamedString("a"),
+			err:    "",
+		},
+		{
+			desp:   "slice of named type",
+			data:   []string{"a", "b"},
+			typ:    reflect.TypeOf([]testNamedString{}),
+			expect: []testNamedString{"a", "b"},
+			err:    "",
+		},
+		{
+			desp:   "pointer",
+			data:   []string{"1"},
+			typ:    reflect.TypeOf((*int)(nil)),
+			expect: func() *int { i := 1; return &i }(),
+			err:    "",
+		},
+		{
+			desp:   "array",
+			data:   []string{"1"},
+			typ:    reflect.TypeOf([2]int{}),
+			expect: [2]int{1, 0},
+			err:    "",
+		},
+		{
+			desp:   "array overflow",
+			data:   []string{"1", "2", "3"},
+			typ:    reflect.TypeOf([2]int{}),
+			expect: nil,
+			err:    `Cann't convert 3 elements to type \[2\]int`,
+		},
+		{
+			desp:   "bytes",
+			data:   []string{"104", "105"},
+			typ:    reflect.TypeOf([]byte{}),
+			expect: []byte("hi"),
+			err:    "",
+		},
+		{
+			desp:   "base64 bytes",
+			data:   []string{"aGVsbG8="},
+			typ:    reflect.TypeOf(Base64Bytes{}),
+			expect: Base64Bytes("hello"),
+			err:    "",
+		},
+		{
+			desp:   "invalid base64 bytes",
+			data:   []string{"hello"},
+			typ:    reflect.TypeOf(Base64Bytes{}),
+			expect: nil,
+			err:    "Cann't convert hello to type conv.Base64Bytes: it must be base64 encoded",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desp, func(t *testing.T) {
@@ -843,6 +941,30 @@ func TestToString(t *testing.T) {
 			expect: "1s",
 			err:    "",
 		},
+		{
+			desp:   "named type",
+			i:      testNamedString("a"),
+			expect: "a",
+			err:    "",
+		},
+		{
+			desp:   "pointer",
+			i:      func() *float64 { f := 1.5; return &f }(),
+			expect: "1.5",
+			err:    "",
+		},
+		{
+			desp:   "nil pointer",
+			i:      (*int)(nil),
+			expect: "",
+			err:    `Cann't convert nil pointer '\*int' to string`,
+		},
+		{
+			desp:   "base64 bytes",
+			i:      Base64Bytes("hello"),
+			expect: "aGVsbG8=",
+			err:    "",
+		},
 		{
 			desp:   "unsupport type",
 			i:      testing.T{},
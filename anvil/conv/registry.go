@@ -0,0 +1,215 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package conv
+
+import (
+	"context"
+	"encoding"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// ConvertFunc converts []string to the value of registered type.
+type ConvertFunc func(ctx context.Context, data []string) (any, error)
+
+// ToStringFunc converts the value of registered type to string.
+type ToStringFunc func(i any) (string, error)
+
+var (
+	// converters is the registered ConvertFunc of type, it is consulted before kind based conversion.
+	converters sync.Map
+	// toStrings is the registered ToStringFunc of type.
+	toStrings sync.Map
+
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// TimeLayouts is the layouts to parse time.Time, they are tried in order.
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	time.DateTime,
+	"2006-01-02T15:04:05",
+	time.DateOnly,
+}
+
+func init() {
+	Register(func(_ context.Context, data []string) (time.Duration, error) {
+		return time.ParseDuration(data[0])
+	})
+	Register(func(_ context.Context, data []string) (time.Time, error) {
+		return parseTime(data[0])
+	})
+	Register(func(_ context.Context, data []string) (*url.URL, error) {
+		return url.Parse(data[0])
+	})
+	Register(func(_ context.Context, data []string) (*regexp.Regexp, error) {
+		return regexp.Compile(data[0])
+	})
+	Register(func(_ context.Context, data []string) (ByteSize, error) {
+		return ParseByteSize(data[0])
+	})
+
+	RegisterToString(func(d time.Duration) (string, error) {
+		return d.String(), nil
+	})
+	RegisterToString(func(t time.Time) (string, error) {
+		return t.Format(time.RFC3339Nano), nil
+	})
+	RegisterToString(func(u *url.URL) (string, error) {
+		return u.String(), nil
+	})
+	RegisterToString(func(r *regexp.Regexp) (string, error) {
+		return r.String(), nil
+	})
+	RegisterToString(func(s ByteSize) (string, error) {
+		return s.String(), nil
+	})
+}
+
+// Register register the convert function of T, it is consulted before the kind based conversion
+// in ConvertTo, so it can overwrite the builtin conversion. The function registered for pointer
+// type (e.g. *url.URL) is also used for the element type (e.g. url.URL), and vice versa.
+func Register[T any](fn func(ctx context.Context, data []string) (T, error)) {
+	converters.Store(reflect.TypeOf((*T)(nil)).Elem(), ConvertFunc(func(ctx context.Context, data []string) (any, error) {
+		return fn(ctx, data)
+	}))
+}
+
+// RegisterToString register the function to convert T to string, it is consulted before the
+// builtin conversion in ToString.
+func RegisterToString[T any](fn func(v T) (string, error)) {
+	toStrings.Store(reflect.TypeOf((*T)(nil)).Elem(), ToStringFunc(func(i any) (string, error) {
+		return fn(i.(T))
+	}))
+}
+
+// HasConverter return true if typ can be converted by the registered function or
+// encoding.TextUnmarshaler, rather than by the kind of typ.
+func HasConverter(typ reflect.Type) bool {
+	_, ok := lookupConverter(typ)
+	return ok
+}
+
+// HasToString return true if typ can be converted to string by the registered function or
+// encoding.TextMarshaler, rather than by the kind of typ.
+func HasToString(typ reflect.Type) bool {
+	if _, ok := toStrings.Load(typ); ok {
+		return true
+	}
+	return typ.Implements(textMarshalerType)
+}
+
+// lookupConverter return the registered function or encoding.TextUnmarshaler of typ.
+func lookupConverter(typ reflect.Type) (ConvertFunc, bool) {
+	if fn, ok := converters.Load(typ); ok {
+		return fn.(ConvertFunc), true
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		if fn, ok := converters.Load(typ.Elem()); ok {
+			return addrOf(typ.Elem(), fn.(ConvertFunc)), true
+		}
+	} else if fn, ok := converters.Load(reflect.PointerTo(typ)); ok {
+		return elemOf(fn.(ConvertFunc)), true
+	}
+
+	switch {
+	case typ.Kind() == reflect.Ptr && typ.Implements(textUnmarshalerType):
+		return unmarshalText(typ.Elem(), true), true
+	case typ.Kind() != reflect.Ptr && reflect.PointerTo(typ).Implements(textUnmarshalerType):
+		return unmarshalText(typ, false), true
+	}
+	return nil, false
+}
+
+// addrOf return the function which return the pointer of value converted by fn.
+func addrOf(typ reflect.Type, fn ConvertFunc) ConvertFunc {
+	return func(ctx context.Context, data []string) (any, error) {
+		v, err := fn(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+
+		ret := reflect.New(typ)
+		ret.Elem().Set(reflect.ValueOf(v))
+		return ret.Interface(), nil
+	}
+}
+
+// elemOf return the function which return the element of pointer converted by fn.
+func elemOf(fn ConvertFunc) ConvertFunc {
+	return func(ctx context.Context, data []string) (any, error) {
+		v, err := fn(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+
+		rv := reflect.ValueOf(v)
+		if rv.IsNil() {
+			return nil, xerrors.Errorf("Cann't convert %s to type %s, the value is nil", data[0], rv.Type().Elem())
+		}
+		return rv.Elem().Interface(), nil
+	}
+}
+
+func unmarshalText(typ reflect.Type, ptr bool) ConvertFunc {
+	return func(_ context.Context, data []string) (any, error) {
+		ret := reflect.New(typ)
+		err := ret.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(data[0]))
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "Cann't convert %s to type %s", data[0], typ.String())
+		}
+
+		if ptr {
+			return ret.Interface(), nil
+		}
+		return ret.Elem().Interface(), nil
+	}
+}
+
+func lookupToString(i any) (ToStringFunc, bool) {
+	if fn, ok := toStrings.Load(reflect.TypeOf(i)); ok {
+		return fn.(ToStringFunc), true
+	}
+
+	if m, ok := i.(encoding.TextMarshaler); ok {
+		return func(any) (string, error) {
+			data, err := m.MarshalText()
+			return string(data), err
+		}, true
+	}
+	return nil, false
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range TimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, xerrors.Errorf("Cann't convert %s to type time.Time, it must match one of layouts %v",
+		s, TimeLayouts)
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package conv
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+type testUpperString string
+
+func TestConvertToRegisteredType(t *testing.T) {
+	Register(func(_ context.Context, data []string) (testUpperString, error) {
+		return testUpperString(strings.ToUpper(data[0])), nil
+	})
+
+	type testCase struct {
+		desp   string
+		typ    reflect.Type
+		data   []string
+		expect any
+		err    string
+	}
+	testCases := []testCase{
+		{
+			desp:   "registered type",
+			typ:    reflect.TypeOf(testUpperString("")),
+			data:   []string{"abc"},
+			expect: testUpperString("ABC"),
+		},
+		{
+			desp:   "slice of registered type",
+			typ:    reflect.TypeOf([]testUpperString{}),
+			data:   []string{"a", "b"},
+			expect: []testUpperString{"A", "B"},
+		},
+		{
+			desp:   "time with RFC3339",
+			typ:    reflect.TypeOf(time.Time{}),
+			data:   []string{"2025-01-02T03:04:05Z"},
+			expect: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			desp:   "time with date only",
+			typ:    reflect.TypeOf(time.Time{}),
+			data:   []string{"2025-01-02"},
+			expect: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desp: "invalid time",
+			typ:  reflect.TypeOf(time.Time{}),
+			data: []string{"yesterday"},
+			err:  "Cann't convert yesterday to type time.Time",
+		},
+		{
+			desp:   "url pointer",
+			typ:    reflect.TypeOf(&url.URL{}),
+			data:   []string{"https://example.com/a?b=c"},
+			expect: &url.URL{Scheme: "https", Host: "example.com", Path: "/a", RawQuery: "b=c"},
+		},
+		{
+			desp:   "url value",
+			typ:    reflect.TypeOf(url.URL{}),
+			data:   []string{"https://example.com"},
+			expect: url.URL{Scheme: "https", Host: "example.com"},
+		},
+		{
+			desp:   "regexp",
+			typ:    reflect.TypeOf(&regexp.Regexp{}),
+			data:   []string{"^a+$"},
+			expect: regexp.MustCompile("^a+$"),
+		},
+		{
+			desp: "invalid regexp",
+			typ:  reflect.TypeOf(&regexp.Regexp{}),
+			data: []string{"("},
+			err:  "missing closing",
+		},
+		{
+			desp:   "byte size",
+			typ:    reflect.TypeOf(ByteSize(0)),
+			data:   []string{"10MiB"},
+			expect: 10 * MiB,
+		},
+		{
+			desp:   "net ip",
+			typ:    reflect.TypeOf(net.IP{}),
+			data:   []string{"10.0.0.1"},
+			expect: net.ParseIP("10.0.0.1"),
+		},
+		{
+			desp:   "slice of net ip",
+			typ:    reflect.TypeOf([]net.IP{}),
+			data:   []string{"10.0.0.1", "::1"},
+			expect: []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")},
+		},
+		{
+			desp:   "netip prefix",
+			typ:    reflect.TypeOf(netip.Prefix{}),
+			data:   []string{"10.0.0.0/8"},
+			expect: netip.MustParsePrefix("10.0.0.0/8"),
+		},
+		{
+			desp: "invalid netip prefix",
+			typ:  reflect.TypeOf(netip.Prefix{}),
+			data: []string{"10.0.0.0"},
+			err:  "Cann't convert 10.0.0.0 to type netip.Prefix",
+		},
+		{
+			desp:   "slog level",
+			typ:    reflect.TypeOf(slog.LevelInfo),
+			data:   []string{"WARN"},
+			expect: slog.LevelWarn,
+		},
+		{
+			desp:   "text unmarshaler pointer",
+			typ:    reflect.TypeOf(new(slog.Level)),
+			data:   []string{"DEBUG"},
+			expect: func() *slog.Level { l := slog.LevelDebug; return &l }(),
+		},
+		{
+			desp: "empty data",
+			typ:  reflect.TypeOf(time.Time{}),
+			data: []string{},
+			err:  "Cann't convert empty data to type time.Time",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			actual, err := ConvertTo(context.Background(), tc.typ, tc.data)
+			if tc.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(actual).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestToStringRegisteredType(t *testing.T) {
+	RegisterToString(func(s testUpperString) (string, error) {
+		return strings.ToLower(string(s)), nil
+	})
+
+	type testCase struct {
+		desp   string
+		i      any
+		expect string
+	}
+	testCases := []testCase{
+		{
+			desp:   "registered type",
+			i:      testUpperString("ABC"),
+			expect: "abc",
+		},
+		{
+			desp:   "time",
+			i:      time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			expect: "2025-01-02T03:04:05Z",
+		},
+		{
+			desp:   "url",
+			i:      &url.URL{Scheme: "https", Host: "example.com"},
+			expect: "https://example.com",
+		},
+		{
+			desp:   "regexp",
+			i:      regexp.MustCompile("^a+$"),
+			expect: "^a+$",
+		},
+		{
+			desp:   "byte size",
+			i:      10 * MiB,
+			expect: "10MiB",
+		},
+		{
+			desp:   "text marshaler",
+			i:      net.ParseIP("10.0.0.1"),
+			expect: "10.0.0.1",
+		},
+		{
+			desp:   "slog level",
+			i:      slog.LevelWarn,
+			expect: "WARN",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			actual, err := ToString(tc.i)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(actual).To(Equal(tc.expect))
+
+			if HasConverter(reflect.TypeOf(tc.i)) {
+				v, err := ConvertTo(context.Background(), reflect.TypeOf(tc.i), []string{actual})
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(v).To(Equal(tc.i))
+			}
+		})
+	}
+}
@@ -33,19 +33,39 @@ import (
 func SafeRun(ctx context.Context, cmd func(context.Context)) {
 	defer func() {
 		if r := recover(); r != nil {
-			var err error
-			if ierr, ok := r.(error); ok {
-				err = ierr
-			} else {
-				err = xerrors.Errorf("Recover from: '%v', stack: '%v'", r, string(debug.Stack()))
-			}
-
-			slogctx.FromCtx(ctx).ErrorContext(
-				ctx, "Panic when execute runnable",
-				slog.Any("Error", err),
-			)
+			logPanic(ctx, recoverError(r))
 		}
 	}()
 
 	cmd(ctx)
 }
+
+// SafeRunWithError will execute cmd and recover any panic, the panic is returned as error without
+// logging, so the caller can handle it once as the error of cmd.
+func SafeRunWithError(ctx context.Context, cmd func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoverError(r)
+			if _, ok := r.(error); ok {
+				err = xerrors.Wrapf(err, "Recover from panic")
+			}
+		}
+	}()
+
+	return cmd(ctx)
+}
+
+// recoverError convert the recovered value to error, the stack is attached if it's not an error.
+func recoverError(r any) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+	return xerrors.Errorf("Recover from: '%v', stack: '%v'", r, string(debug.Stack()))
+}
+
+func logPanic(ctx context.Context, err error) {
+	slogctx.FromCtx(ctx).ErrorContext(
+		ctx, "Panic when execute runnable",
+		slog.Any("Error", err),
+	)
+}
@@ -61,3 +61,54 @@ func TestSafeRun(t *testing.T) {
 		})
 	}
 }
+
+func TestSafeRunWithError(t *testing.T) {
+	type testCase struct {
+		desp     string
+		cmd      func(context.Context) error
+		expected string
+	}
+	testCases := []testCase{
+		{
+			desp: "normal runnable",
+			cmd: func(context.Context) error {
+				return nil
+			},
+			expected: "",
+		},
+		{
+			desp: "return error",
+			cmd: func(context.Context) error {
+				return xerrors.Errorf("failed")
+			},
+			expected: "^failed$",
+		},
+		{
+			desp: "panic on error",
+			cmd: func(context.Context) error {
+				panic(xerrors.ErrNotFound)
+			},
+			expected: "^Recover from panic: " + xerrors.ErrNotFound.Error() + "$",
+		},
+		{
+			desp: "panic on int",
+			cmd: func(context.Context) error {
+				panic(1)
+			},
+			expected: "^Recover from: '1', stack: ",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := SafeRunWithError(context.Background(), tc.cmd)
+			if tc.expected == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(MatchRegexp(tc.expected))
+		})
+	}
+}
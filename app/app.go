@@ -48,6 +48,16 @@ func Get(ctx context.Context, key string, opts ...props.GetOption) (any, error)
 	return app.Get(ctx, key, opts...)
 }
 
+// UpdateProperties wraps Application.UpdateProperties function.
+func UpdateProperties(ctx context.Context, fn func(p props.ConfigurableProperties) error) error {
+	return app.UpdateProperties(ctx, fn)
+}
+
+// Args wraps Application.Args function.
+func Args() []string {
+	return app.Args()
+}
+
 // Run wraps Application.Run function.
 func Run(ctx context.Context, opts ...Option) error {
 	return app.Run(ctx, opts...)
@@ -54,3 +54,9 @@ type ApplicationListener interface {
 type AsyncApplicationListener interface {
 	OnApplicationEventAsync(ctx context.Context, event ApplicationEvent)
 }
+
+// ConditionalApplicationListener is to be implemented by listener bean which only handle part of
+// events, the listeners of bean (including the listener methods) are skipped if it return false.
+type ConditionalApplicationListener interface {
+	SupportsEvent(ctx context.Context, event ApplicationEvent) bool
+}
@@ -21,10 +21,60 @@ package app
 
 import (
 	"context"
+
+	"github.com/anyvoxel/airmid/ioc"
+)
+
+const (
+	// EventErrorPolicyStop stop notifying the rest listeners and return the error of listener.
+	EventErrorPolicyStop = "stop"
+	// EventErrorPolicyContinue log the error of listener and continue notifying the rest listeners.
+	EventErrorPolicyContinue = "continue"
+	// EventErrorPolicyCollect continue notifying the rest listeners and return the joined errors.
+	EventErrorPolicyCollect = "collect"
 )
 
 // ApplicationEventPublisher is the interface that encapsulates event publication functionality.
 type ApplicationEventPublisher interface {
 	// PublishEvent will notify all matching listeners registered with this application of an application event.
-	PublishEvent(ctx context.Context, event ApplicationEvent)
+	// The listeners are notified by priority descending, and the error (including panic) of sync listener
+	// is handled by the policy of 'airmid.event.error.policy', which must be 'stop', 'continue' or 'collect'.
+	PublishEvent(ctx context.Context, event ApplicationEvent) error
+}
+
+// ApplicationEventMulticaster is the ApplicationEventPublisher which manage the listeners, the
+// listeners can be added & removed at any time, e.g. by Subscribe in tests or non-bean components.
+type ApplicationEventMulticaster interface {
+	ApplicationEventPublisher
+
+	// AddListenerInvoker add the invoker to be notified of all published events, it return
+	// the function to remove the invoker.
+	AddListenerInvoker(invoker ListenerInvoker, opts ...ListenerOption) (remove func())
+}
+
+// ListenerOption applies a configuration option value to a listener.
+type ListenerOption func(*listenerOption)
+
+// listenerOption contains configuration options for a listener.
+type listenerOption struct {
+	// priority is the priority of listener, nil means the lowest one, which is notified last
+	priority *ioc.BeanPriority
+	// condition report whether the listener should be notified of event, nil means always
+	condition func(ctx context.Context, event ApplicationEvent) bool
+}
+
+// WithListenerPriority set the priority of listener, the higher priority one is notified first,
+// and the listeners with same priority are notified by the added order.
+func WithListenerPriority(priority ioc.BeanPriority) ListenerOption {
+	return func(o *listenerOption) {
+		o.priority = &priority
+	}
+}
+
+// WithListenerCondition set the condition of listener, the listener is notified only if
+// condition return true.
+func WithListenerCondition(condition func(ctx context.Context, event ApplicationEvent) bool) ListenerOption {
+	return func(o *listenerOption) {
+		o.condition = condition
+	}
 }
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"reflect"
+)
+
+// Subscribe register fn as the listener of events which type is E on multicaster, the event which
+// isn't E is ignored, and E can be an interface to receive all events implementing it. It doesn't
+// require the listener to be bean, and the event is delivered by type assertion without reflection.
+// It return the function to unsubscribe, e.g.
+//
+//	unsubscribe := app.Subscribe(app.DefaultApp(), func(ctx context.Context, ev app.PropertiesChangedEvent) {
+//		...
+//	})
+//	defer unsubscribe()
+func Subscribe[E ApplicationEvent](
+	multicaster ApplicationEventMulticaster,
+	fn func(ctx context.Context, event E),
+	opts ...ListenerOption,
+) (unsubscribe func()) {
+	return SubscribeWithError(multicaster, func(ctx context.Context, event E) error {
+		fn(ctx, event)
+		return nil
+	}, opts...)
+}
+
+// SubscribeWithError is the variant of Subscribe which fn return error, the error is handled by
+// the error policy of publisher.
+func SubscribeWithError[E ApplicationEvent](
+	multicaster ApplicationEventMulticaster,
+	fn func(ctx context.Context, event E) error,
+	opts ...ListenerOption,
+) (unsubscribe func()) {
+	return multicaster.AddListenerInvoker(&typedListenerInvoker[E]{fn: fn}, opts...)
+}
+
+// Publish notify the listeners of event on publisher, it's the typed variant of PublishEvent.
+func Publish[E ApplicationEvent](ctx context.Context, publisher ApplicationEventPublisher, event E) error {
+	return publisher.PublishEvent(ctx, event)
+}
+
+// typedListenerInvoker invoke fn with the event which type is E.
+type typedListenerInvoker[E ApplicationEvent] struct {
+	fn func(ctx context.Context, event E) error
+}
+
+// Invoke implement ListenerInvoker.Invoke.
+func (i *typedListenerInvoker[E]) Invoke(ctx context.Context, event ApplicationEvent) error {
+	if e, ok := event.(E); ok {
+		return i.fn(ctx, e)
+	}
+	return nil
+}
+
+// supportsEventType implement eventTypeMatcher, the nil event is never E.
+func (*typedListenerInvoker[E]) supportsEventType(typ reflect.Type) bool {
+	return typ != nil && typ.AssignableTo(reflect.TypeFor[E]())
+}
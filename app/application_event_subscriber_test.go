@@ -0,0 +1,306 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+func TestSubscribe(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	app := NewApplication()
+
+	var typed []*testFnEventListenerEvent
+	unsubscribe := Subscribe(app, func(_ context.Context, ev *testFnEventListenerEvent) {
+		typed = append(typed, ev)
+	})
+	all := 0
+	unsubscribeAll := Subscribe(app, func(_ context.Context, _ ApplicationEvent) {
+		all++
+	})
+
+	ev := &testFnEventListenerEvent{}
+	Publish(ctx, app, ev)
+	app.PublishEvent(ctx, NewDefaultApplicationEvent(nil))
+	app.PublishEvent(ctx, nil)
+	g.Expect(typed).To(Equal([]*testFnEventListenerEvent{ev}))
+	g.Expect(all).To(Equal(2))
+
+	// The unsubscribe is idempotent, and it doesn't affect other listeners
+	unsubscribe()
+	unsubscribe()
+	Publish(ctx, app, ev)
+	g.Expect(typed).To(HaveLen(1))
+	g.Expect(all).To(Equal(3))
+
+	unsubscribeAll()
+	Publish[ApplicationEvent](ctx, app, ev)
+	g.Expect(all).To(Equal(3))
+}
+
+func TestSubscribeConcurrently(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	app := NewApplication()
+
+	var mu sync.Mutex
+	count := 0
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			unsubscribe := Subscribe(app, func(_ context.Context, _ *testFnEventListenerEvent) {
+				mu.Lock()
+				count++
+				mu.Unlock()
+			})
+			Publish(ctx, app, &testFnEventListenerEvent{})
+			unsubscribe()
+		}()
+		go func() {
+			defer wg.Done()
+			Publish(ctx, app, &testFnEventListenerEvent{})
+		}()
+	}
+	wg.Wait()
+
+	// Each listener see at least the event published by itself
+	g.Expect(count).To(BeNumerically(">=", 10))
+	g.Expect(app.(*airmidApplication).listenerInvoker).To(BeEmpty())
+}
+
+func TestSubscribeWithPriorityAndCondition(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	app := NewApplication()
+
+	order := []string{}
+	record := func(name string) func(context.Context, ApplicationEvent) {
+		return func(context.Context, ApplicationEvent) {
+			order = append(order, name)
+		}
+	}
+	Subscribe(app, record("none1"))
+	Subscribe(app, record("low"), WithListenerPriority(-1))
+	Subscribe(app, record("high"), WithListenerPriority(10))
+	Subscribe(app, record("none2"))
+	Subscribe(app, record("zero1"), WithListenerPriority(0))
+	Subscribe(app, record("zero2"), WithListenerPriority(0))
+	Subscribe(app, record("skipped"), WithListenerPriority(100),
+		WithListenerCondition(func(_ context.Context, event ApplicationEvent) bool {
+			_, ok := event.(*testFnEventListenerEvent)
+			return ok
+		}))
+
+	g.Expect(app.PublishEvent(ctx, NewDefaultApplicationEvent(nil))).To(Succeed())
+	g.Expect(order).To(Equal([]string{"high", "zero1", "zero2", "low", "none1", "none2"}))
+
+	order = order[:0]
+	g.Expect(app.PublishEvent(ctx, &testFnEventListenerEvent{})).To(Succeed())
+	g.Expect(order).To(Equal([]string{"skipped", "high", "zero1", "zero2", "low", "none1", "none2"}))
+}
+
+func TestPublishEventErrorPolicy(t *testing.T) {
+	type testCase struct {
+		desp     string
+		policy   string
+		expected []string
+		called   []string
+		logged   int
+	}
+	testCases := []testCase{
+		{
+			desp:     "default policy is collect",
+			policy:   "",
+			expected: []string{"l1 failed", "Recover from: 'l2 panic'"},
+			called:   []string{"l1", "l2", "l3"},
+		},
+		{
+			desp:     "collect policy",
+			policy:   EventErrorPolicyCollect,
+			expected: []string{"l1 failed", "Recover from: 'l2 panic'"},
+			called:   []string{"l1", "l2", "l3"},
+		},
+		{
+			desp:     "stop policy",
+			policy:   EventErrorPolicyStop,
+			expected: []string{"l1 failed"},
+			called:   []string{"l1"},
+		},
+		{
+			desp:     "continue policy",
+			policy:   EventErrorPolicyContinue,
+			expected: nil,
+			called:   []string{"l1", "l2", "l3"},
+			logged:   2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			buf := &bytes.Buffer{}
+			ctx := slogctx.NewCtx(context.Background(), slog.New(slog.NewTextHandler(buf, nil)))
+			app := NewApplication().(*airmidApplication)
+			app.props.eventErrorPolicy = tc.policy
+
+			called := []string{}
+			SubscribeWithError(app, func(context.Context, ApplicationEvent) error {
+				called = append(called, "l1")
+				return xerrors.Errorf("l1 failed")
+			})
+			Subscribe(app, func(context.Context, ApplicationEvent) {
+				called = append(called, "l2")
+				panic("l2 panic")
+			})
+			Subscribe(app, func(context.Context, ApplicationEvent) {
+				called = append(called, "l3")
+			})
+
+			err := Publish[ApplicationEvent](ctx, app, NewDefaultApplicationEvent(nil))
+			g.Expect(called).To(Equal(tc.called))
+			// The panic is logged only once by the continue policy
+			g.Expect(strings.Count(buf.String(), "Listener failed")).To(Equal(tc.logged))
+			g.Expect(buf.String()).ToNot(ContainSubstring("Panic when execute runnable"))
+			if len(tc.expected) == 0 {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			for _, msg := range tc.expected {
+				g.Expect(err.Error()).To(ContainSubstring(msg))
+			}
+		})
+	}
+}
+
+func TestValidateEventErrorPolicy(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(validateEventErrorPolicy(EventErrorPolicyStop)).To(Succeed())
+	g.Expect(validateEventErrorPolicy(EventErrorPolicyContinue)).To(Succeed())
+	g.Expect(validateEventErrorPolicy(EventErrorPolicyCollect)).To(Succeed())
+
+	err := validateEventErrorPolicy("ignore")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(Equal("Invalid event error policy 'ignore', it must be 'stop', 'continue' or 'collect'"))
+}
+
+type testConfigEvent interface {
+	ApplicationEvent
+	configName() string
+}
+
+type testFileConfigEvent struct {
+	ApplicationEvent
+}
+
+func (testFileConfigEvent) configName() string {
+	return "file"
+}
+
+type testEnvConfigEvent struct {
+	ApplicationEvent
+}
+
+func (*testEnvConfigEvent) configName() string {
+	return "env"
+}
+
+type testConfigEventListener struct {
+	names []string
+	files int
+}
+
+func (l *testConfigEventListener) OnConfigEvent(_ context.Context, event testConfigEvent) {
+	l.names = append(l.names, event.configName())
+}
+
+func (l *testConfigEventListener) OnFileConfigEvent(_ context.Context, _ testFileConfigEvent) {
+	l.files++
+}
+
+func TestPublishEventHierarchy(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	app := NewApplication().(*airmidApplication)
+	p := &applicationListenerDetector{
+		app:            app,
+		singletonNames: map[string]bool{"l": true},
+	}
+	l := &testConfigEventListener{}
+	_, err := p.PostProcessAfterInitialization(ctx, l, "l")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	typed := []string{}
+	Subscribe(app, func(_ context.Context, event testConfigEvent) {
+		typed = append(typed, event.configName())
+	})
+
+	g.Expect(app.PublishEvent(ctx, testFileConfigEvent{})).To(Succeed())
+	g.Expect(app.PublishEvent(ctx, &testEnvConfigEvent{})).To(Succeed())
+	// The value type doesn't implement the interface with pointer receiver
+	g.Expect(app.PublishEvent(ctx, testEnvConfigEvent{})).To(Succeed())
+	g.Expect(app.PublishEvent(ctx, NewDefaultApplicationEvent(nil))).To(Succeed())
+	g.Expect(l.names).To(Equal([]string{"file", "env"}))
+	g.Expect(l.files).To(Equal(1))
+	g.Expect(typed).To(Equal([]string{"file", "env"}))
+}
+
+func TestPublishEventCache(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	app := NewApplication().(*airmidApplication)
+
+	count := 0
+	unsubscribe := Subscribe(app, func(context.Context, *testFnEventListenerEvent) {
+		count++
+	})
+	g.Expect(app.matchedListenerInvokers(NewDefaultApplicationEvent(nil))).To(BeEmpty())
+	g.Expect(app.matchedListenerInvokers(&testFnEventListenerEvent{})).To(HaveLen(1))
+	cached, ok := app.listenerCache.Load(reflect.TypeOf(&testFnEventListenerEvent{}))
+	g.Expect(ok).To(BeTrue())
+	g.Expect(cached).To(HaveLen(1))
+
+	// The cache is reset when the listeners are changed
+	Subscribe(app, func(context.Context, ApplicationEvent) {
+		count++
+	})
+	_, ok = app.listenerCache.Load(reflect.TypeOf(&testFnEventListenerEvent{}))
+	g.Expect(ok).To(BeFalse())
+	g.Expect(app.PublishEvent(ctx, &testFnEventListenerEvent{})).To(Succeed())
+	g.Expect(count).To(Equal(2))
+
+	unsubscribe()
+	g.Expect(app.PublishEvent(ctx, &testFnEventListenerEvent{})).To(Succeed())
+	g.Expect(count).To(Equal(3))
+	g.Expect(app.matchedListenerInvokers(nil)).To(BeEmpty())
+}
@@ -53,6 +53,7 @@ func (l *applicationListenerDetector) PostProcessAfterInitialization(
 	}
 
 	// TODO: only invoke on Singleton scope
+	opts := listenerOptions(obj)
 	vv := reflect.ValueOf(obj)
 	n := vv.Type().NumMethod()
 
@@ -75,7 +76,7 @@ func (l *applicationListenerDetector) PostProcessAfterInitialization(
 			return nil, err
 		}
 
-		l.app.listenerInvoker = append(l.app.listenerInvoker, invoker)
+		l.app.AddListenerInvoker(invoker, opts...)
 	}
 
 	invoker, err := NewObjectListenerInvoker(obj, l.app)
@@ -91,8 +92,21 @@ func (l *applicationListenerDetector) PostProcessAfterInitialization(
 			slog.Any("Error", err),
 		)
 	} else {
-		l.app.listenerInvoker = append(l.app.listenerInvoker, invoker)
+		l.app.AddListenerInvoker(invoker, opts...)
 	}
 
 	return obj, nil
 }
+
+// listenerOptions return the options of listeners in bean, the priority is the bean's
+// BeanPriorityOrder and the condition is the bean's ConditionalApplicationListener.
+func listenerOptions(obj any) []ListenerOption {
+	opts := []ListenerOption{}
+	if o := ioc.IndirectTo[ioc.BeanPriorityOrder](obj); o != nil {
+		opts = append(opts, WithListenerPriority(o.GetPriority()))
+	}
+	if o := ioc.IndirectTo[ConditionalApplicationListener](obj); o != nil {
+		opts = append(opts, WithListenerCondition(o.SupportsEvent))
+	}
+	return opts
+}
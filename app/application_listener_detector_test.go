@@ -70,7 +70,7 @@ func TestPostProcessAfterInitialization(t *testing.T) {
 		wg:    &wg,
 	}
 	app := &airmidApplication{
-		listenerInvoker: make([]ListenerInvoker, 0),
+		listenerInvoker: make([]*registeredListenerInvoker, 0),
 		gpool: func() *ants.Pool {
 			p, _ := ants.NewPool(10)
 			return p
@@ -106,7 +106,7 @@ func TestPostProcessBeanDefinition(t *testing.T) {
 	g := NewWithT(t)
 
 	app := &airmidApplication{
-		listenerInvoker: make([]ListenerInvoker, 0),
+		listenerInvoker: make([]*registeredListenerInvoker, 0),
 	}
 	p := &applicationListenerDetector{
 		app:            app,
@@ -119,3 +119,62 @@ func TestPostProcessBeanDefinition(t *testing.T) {
 		"l2": false,
 	}))
 }
+
+type testOrderedListener struct {
+	name     string
+	priority ioc.BeanPriority
+	order    *[]string
+}
+
+func (l *testOrderedListener) GetPriority() ioc.BeanPriority {
+	return l.priority
+}
+
+func (l *testOrderedListener) OnApplicationEvent(ctx context.Context, event ApplicationEvent) {
+	*l.order = append(*l.order, l.name)
+}
+
+type testConditionalListener struct {
+	order *[]string
+}
+
+func (l *testConditionalListener) SupportsEvent(ctx context.Context, event ApplicationEvent) bool {
+	_, ok := event.(*testFnEventListenerEvent)
+	return ok
+}
+
+func (l *testConditionalListener) OnEvent(ctx context.Context, event ApplicationEvent) {
+	*l.order = append(*l.order, "conditional")
+}
+
+func TestPostProcessAfterInitializationWithOptions(t *testing.T) {
+	g := NewWithT(t)
+	order := []string{}
+	app := &airmidApplication{
+		listenerInvoker: make([]*registeredListenerInvoker, 0),
+	}
+	p := &applicationListenerDetector{
+		app: app,
+		singletonNames: map[string]bool{
+			"conditional": true,
+			"low":         true,
+			"high":        true,
+		},
+	}
+	beans := map[string]any{
+		"conditional": &testConditionalListener{order: &order},
+		"low":         &testOrderedListener{name: "low", priority: -1, order: &order},
+		"high":        &testOrderedListener{name: "high", priority: 1, order: &order},
+	}
+	for _, name := range []string{"conditional", "low", "high"} {
+		_, err := p.PostProcessAfterInitialization(context.Background(), beans[name], name)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	g.Expect(app.PublishEvent(context.Background(), NewDefaultApplicationEvent(nil))).To(Succeed())
+	g.Expect(order).To(Equal([]string{"high", "low"}))
+
+	order = order[:0]
+	g.Expect(app.PublishEvent(context.Background(), &testFnEventListenerEvent{})).To(Succeed())
+	g.Expect(order).To(Equal([]string{"high", "low", "conditional"}))
+}
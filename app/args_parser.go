@@ -0,0 +1,146 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"strings"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// parsedFlag is the flag parsed from command line args.
+type parsedFlag struct {
+	// name is the flag name in args which the values come from, e.g. '--port', '-p', it's
+	// the first occurrence for list flag
+	name string
+	// key is the property key of flag, e.g. 'port'
+	key    string
+	values []string
+}
+
+// argsParser parse the command line args with following rules:
+//  1. '--key=value' and '-key=value' set the value of key
+//  2. '-k' is the key which short flag 'k' is mapped to, or 'k' if not mapped,
+//     and '--name' is the key which alias 'name' is mapped to
+//  3. the flag without value is set to 'true', unless the key is declared as value or list flag,
+//     which take the next arg as value, e.g. '--port 80'
+//  4. the repeated flag overrides the previous one, unless the key is declared as list flag,
+//     which accumulate the values into slice
+//  5. the value separated by separator is split into slice, the escaped separator (e.g. '\,')
+//     and the JSON array or object are not split
+//  6. the args which is not flag or after '--' are positional args
+//  7. the unknown flag is reported if the parser is strict.
+type argsParser struct {
+	shortFlags map[string]string
+	aliases    map[string]string
+	valueFlags map[string]bool
+	listFlags  map[string]bool
+	strict     bool
+	// separator is the separator to split the flag value, the value isn't split if it's empty.
+	separator string
+	// knownFlags is the known keys in strict mode, the sub key (e.g. 'db.host' for 'db') is known too.
+	knownFlags []string
+}
+
+func (p *argsParser) parse(args []string) ([]*parsedFlag, []string, error) {
+	flags := []*parsedFlag{}
+	flagsByKey := map[string]*parsedFlag{}
+	positional := []string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		key := p.flagKey(name)
+		if key == "" {
+			continue
+		}
+		if p.strict && !p.isKnown(key) {
+			return nil, nil, xerrors.Errorf("Unknown flag '%v'", name)
+		}
+
+		if !hasValue {
+			value = "true"
+			if p.valueFlags[key] || p.listFlags[key] {
+				if i+1 >= len(args) || isFlagArg(args[i+1]) {
+					return nil, nil, xerrors.Errorf("Flag '%v' requires a value", name)
+				}
+				value = args[i+1]
+				i++
+			}
+		}
+
+		f, ok := flagsByKey[key]
+		if !ok {
+			f = &parsedFlag{name: name, key: key}
+			flagsByKey[key] = f
+			flags = append(flags, f)
+		}
+		values := splitListValue(value, p.separator)
+		if p.listFlags[key] {
+			f.values = append(f.values, values...)
+			continue
+		}
+		f.name = name
+		f.values = values
+	}
+	return flags, positional, nil
+}
+
+func (p *argsParser) flagKey(name string) string {
+	if strings.HasPrefix(name, "--") {
+		key := strings.TrimLeft(name, "-")
+		if alias, ok := p.aliases[key]; ok {
+			return alias
+		}
+		return key
+	}
+
+	key := strings.TrimLeft(name, "-")
+	if short, ok := p.shortFlags[key]; ok {
+		return short
+	}
+	return key
+}
+
+func (p *argsParser) isKnown(key string) bool {
+	for _, known := range p.knownFlags {
+		if key == known || strings.HasPrefix(key, known+".") || strings.HasPrefix(key, known+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// isFlagArg return true if the arg is flag or terminator, the negative number is treated as value.
+func isFlagArg(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	return arg[1] < '0' || arg[1] > '9'
+}
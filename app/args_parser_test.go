@@ -0,0 +1,198 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestArgsParserParse(t *testing.T) {
+	type testCase struct {
+		desp       string
+		parser     argsParser
+		args       []string
+		flags      []*parsedFlag
+		positional []string
+		err        string
+	}
+	testCases := []testCase{
+		{
+			desp: "long flags",
+			parser: argsParser{
+				valueFlags: map[string]bool{"port": true, "flag": true},
+			},
+			args: []string{"--host=localhost", "--port", "8080", "-flag", "false", "-ok", "--debug", "-x=-1"},
+			flags: []*parsedFlag{
+				{name: "--host", key: "host", values: []string{"localhost"}},
+				{name: "--port", key: "port", values: []string{"8080"}},
+				{name: "-flag", key: "flag", values: []string{"false"}},
+				{name: "-ok", key: "ok", values: []string{"true"}},
+				{name: "--debug", key: "debug", values: []string{"true"}},
+				{name: "-x", key: "x", values: []string{"-1"}},
+			},
+			positional: []string{},
+		},
+		{
+			desp: "bare flag before positional arg",
+			args: []string{"--verbose", "file", "--port", "80"},
+			flags: []*parsedFlag{
+				{name: "--verbose", key: "verbose", values: []string{"true"}},
+				{name: "--port", key: "port", values: []string{"true"}},
+			},
+			positional: []string{"file", "80"},
+		},
+		{
+			desp: "missing value",
+			parser: argsParser{
+				valueFlags: map[string]bool{"port": true},
+			},
+			args: []string{"--port", "--debug"},
+			err:  "Flag '--port' requires a value",
+		},
+		{
+			desp: "short flags",
+			parser: argsParser{
+				shortFlags: map[string]string{"p": "server.port"},
+				valueFlags: map[string]bool{"server.port": true, "n": true},
+			},
+			args: []string{"-p", "80", "-v", "-n", "-1"},
+			flags: []*parsedFlag{
+				{name: "-p", key: "server.port", values: []string{"80"}},
+				{name: "-v", key: "v", values: []string{"true"}},
+				{name: "-n", key: "n", values: []string{"-1"}},
+			},
+			positional: []string{},
+		},
+		{
+			desp: "aliases",
+			parser: argsParser{
+				aliases: map[string]string{"help": "airmid.help"},
+			},
+			args: []string{"--help", "-help"},
+			flags: []*parsedFlag{
+				{name: "--help", key: "airmid.help", values: []string{"true"}},
+				{name: "-help", key: "help", values: []string{"true"}},
+			},
+			positional: []string{},
+		},
+		{
+			desp: "repeated flags",
+			parser: argsParser{
+				shortFlags: map[string]string{"p": "port"},
+				listFlags:  map[string]bool{"tag": true},
+				separator:  ",",
+			},
+			args: []string{"--tag", "a", "--port=80", "--tag=b,c", "-p=81", "--tag", "d"},
+			flags: []*parsedFlag{
+				{name: "--tag", key: "tag", values: []string{"a", "b", "c", "d"}},
+				{name: "-p", key: "port", values: []string{"81"}},
+			},
+			positional: []string{},
+		},
+		{
+			desp: "escaped separator and JSON value",
+			parser: argsParser{
+				valueFlags: map[string]bool{"tag": true},
+				separator:  ",",
+			},
+			args: []string{`--dsn=a\,b`, `--servers=[{"host":"a"},{"host":"b"}]`, "--tag", "a,b"},
+			flags: []*parsedFlag{
+				{name: "--dsn", key: "dsn", values: []string{"a,b"}},
+				{name: "--servers", key: "servers", values: []string{`[{"host":"a"},{"host":"b"}]`}},
+				{name: "--tag", key: "tag", values: []string{"a", "b"}},
+			},
+			positional: []string{},
+		},
+		{
+			desp: "custom separator",
+			parser: argsParser{
+				valueFlags: map[string]bool{"dsn": true},
+				separator:  ";",
+			},
+			args: []string{"--hosts=a,b;c", "--dsn", "a,b"},
+			flags: []*parsedFlag{
+				{name: "--hosts", key: "hosts", values: []string{"a,b", "c"}},
+				{name: "--dsn", key: "dsn", values: []string{"a,b"}},
+			},
+			positional: []string{},
+		},
+		{
+			desp:       "no separator",
+			args:       []string{"--dsn=a,b"},
+			flags:      []*parsedFlag{{name: "--dsn", key: "dsn", values: []string{"a,b"}}},
+			positional: []string{},
+		},
+		{
+			desp: "positional args and terminator",
+			args: []string{"run", "--verbose", "file", "-", "--", "--port=80", "x"},
+			flags: []*parsedFlag{
+				{name: "--verbose", key: "verbose", values: []string{"true"}},
+			},
+			positional: []string{"run", "file", "-", "--port=80", "x"},
+		},
+		{
+			desp:       "empty flag name",
+			args:       []string{"---", "--=v"},
+			flags:      []*parsedFlag{},
+			positional: []string{},
+		},
+		{
+			desp: "strict flags",
+			parser: argsParser{
+				valueFlags: map[string]bool{"port": true},
+				strict:     true,
+				knownFlags: []string{"db", "port"},
+			},
+			args: []string{"--db.host=h", "--db[0]=x", "--port", "80"},
+			flags: []*parsedFlag{
+				{name: "--db.host", key: "db.host", values: []string{"h"}},
+				{name: "--db[0]", key: "db[0]", values: []string{"x"}},
+				{name: "--port", key: "port", values: []string{"80"}},
+			},
+			positional: []string{},
+		},
+		{
+			desp: "unknown flag",
+			parser: argsParser{
+				strict:     true,
+				knownFlags: []string{"db"},
+			},
+			args: []string{"--db.host=h", "--dbx=1"},
+			err:  "Unknown flag '--dbx'",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+
+			flags, positional, err := tc.parser.parse(tc.args)
+			if tc.err != "" {
+				g.Expect(err).To(MatchError(tc.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(flags).To(Equal(tc.flags))
+			g.Expect(positional).To(Equal(tc.positional))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+// configWatcher poll the config files in every config dir, and reload the properties
+// when the files are changed, added or removed. The reload is delayed until the files
+// are stable for the debounce duration, and the malformed file will be reported without
+// changing the current properties.
+type configWatcher struct {
+	config *config `airmid:"autowire:airmid.app.config"`
+
+	enabled  bool          `airmid:"value:${airmid.config.watch.enabled:=false}"`
+	interval time.Duration `airmid:"value:${airmid.config.watch.interval:=5s}" validate:"min=1ms"`
+	debounce time.Duration `airmid:"value:${airmid.config.watch.debounce:=1s}" validate:"min=0s"`
+
+	app Application
+
+	poller  poller
+	changes debouncer
+}
+
+var (
+	_ Runner           = (*configWatcher)(nil)
+	_ ApplicationAware = (*configWatcher)(nil)
+)
+
+func (w *configWatcher) SetApplication(application Application) {
+	w.app = application
+}
+
+// Run implement Runner.Run, it will start polling in background if the watcher is enabled. The
+// files loaded at startup is the baseline, so the change after loading is reloaded too.
+func (w *configWatcher) Run(ctx context.Context) {
+	if !w.enabled {
+		return
+	}
+
+	w.changes = debouncer{debounce: w.debounce, applied: w.config.fingerprint}
+	w.poller.start(ctx, w.interval, func(ctx context.Context) time.Duration {
+		return w.poll(ctx, time.Now())
+	})
+}
+
+// Stop implement Runner.Stop, it will wait until the polling exited.
+func (w *configWatcher) Stop(ctx context.Context) {
+	w.poller.stop(ctx)
+}
+
+// poll read the config files at now, and reload them if they are changed and stable, it return
+// the duration to wait before next poll.
+func (w *configWatcher) poll(ctx context.Context, now time.Time) time.Duration {
+	ress, err := w.config.readResources(ctx)
+	if err != nil {
+		slogctx.FromCtx(ctx).ErrorContext(
+			ctx,
+			"config watcher cannot read config files",
+			slog.Any("Error", err),
+		)
+		return w.interval
+	}
+
+	// The fingerprint is applied even if the reload failed, so the malformed file
+	// won't be reported repeatedly until it is changed.
+	apply, wait := w.changes.observe(fingerprintResources(ress), now, w.interval)
+	if apply {
+		w.reload(ctx, ress)
+	}
+	return wait
+}
+
+func (w *configWatcher) reload(ctx context.Context, ress []configResource) {
+	sources, err := parseResources(ctx, ress)
+	if err != nil {
+		slogctx.FromCtx(ctx).ErrorContext(
+			ctx,
+			"config watcher cannot reload config files, keep the current properties",
+			slog.Any("Error", err),
+		)
+		return
+	}
+
+	err = w.app.UpdateProperties(ctx, func(p props.ConfigurableProperties) error {
+		w.config.replaceSources(p, ress, sources)
+		return nil
+	})
+	if err != nil {
+		slogctx.FromCtx(ctx).ErrorContext(
+			ctx,
+			"config watcher cannot refresh properties",
+			slog.Any("Error", err),
+		)
+		return
+	}
+
+	slogctx.FromCtx(ctx).InfoContext(
+		ctx,
+		"config watcher reload config files success",
+	)
+}
+
+func fingerprintResources(ress []configResource) string {
+	h := sha256.New()
+	for _, res := range ress {
+		h.Write([]byte(res.name))
+		h.Write([]byte{0})
+		h.Write(res.data)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
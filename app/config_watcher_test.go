@@ -0,0 +1,193 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigWatcherPoll(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	writeFile := func(dir string, name string, content string) {
+		g.Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)).To(Succeed())
+	}
+	app := NewApplication().(*airmidApplication)
+	getPort := func() any {
+		v, err := app.Get(ctx, "port")
+		g.Expect(err).ToNot(HaveOccurred())
+		return v
+	}
+	c := &config{
+		resourceLocator: &localResourceLocator{
+			configDir: []string{dir1, dir2},
+		},
+		ConfigExtensions: []string{".yml"},
+		ActiveProfiles:   []string{"dev"},
+	}
+	writeFile(dir1, "application.yml", "port: 80")
+	g.Expect(c.loadProperty(ctx, app)).To(Succeed())
+
+	w := &configWatcher{
+		config:   c,
+		enabled:  true,
+		interval: time.Minute,
+		app:      app,
+		changes:  debouncer{debounce: time.Second, applied: c.fingerprint},
+	}
+
+	// The added profile file is reloaded after debounce
+	now := time.Now()
+	writeFile(dir2, "application-dev.yml", "\nport: 8080")
+	// The next poll is scheduled at the end of debounce instead of the interval
+	g.Expect(w.poll(ctx, now)).To(Equal(time.Second))
+	g.Expect(getPort()).To(Equal("80"))
+	g.Expect(w.poll(ctx, now.Add(500*time.Millisecond))).To(Equal(500 * time.Millisecond))
+	g.Expect(getPort()).To(Equal("80"))
+	g.Expect(w.poll(ctx, now.Add(time.Second))).To(Equal(time.Minute))
+	g.Expect(getPort()).To(Equal("8080"))
+	origin, err := app.Origin(ctx, "port")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(origin.String()).To(Equal("file " + filepath.Join(dir2, "application-dev.yml") + " line 2"))
+
+	// The debounce is restarted if the file is changed again
+	now = now.Add(time.Minute)
+	writeFile(dir2, "application-dev.yml", "port: 8081")
+	w.poll(ctx, now)
+	writeFile(dir2, "application-dev.yml", "port: 8082")
+	w.poll(ctx, now.Add(time.Second))
+	g.Expect(getPort()).To(Equal("8080"))
+	w.poll(ctx, now.Add(2*time.Second))
+	g.Expect(getPort()).To(Equal("8082"))
+
+	// The malformed file won't change the current properties
+	now = now.Add(time.Minute)
+	writeFile(dir2, "application-dev.yml", "port: [")
+	w.poll(ctx, now)
+	w.poll(ctx, now.Add(time.Second))
+	g.Expect(getPort()).To(Equal("8082"))
+
+	// The removed file is unloaded
+	now = now.Add(time.Minute)
+	g.Expect(os.Remove(filepath.Join(dir2, "application-dev.yml"))).To(Succeed())
+	writeFile(dir1, "application.yml", "port: 81")
+	w.poll(ctx, now)
+	w.poll(ctx, now.Add(time.Second))
+	g.Expect(getPort()).To(Equal("81"))
+	g.Expect(c.sourceNames).To(Equal([]string{"file " + filepath.Join(dir1, "application.yml")}))
+}
+
+func TestConfigWatcherRunAndStop(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "application.yml"), []byte("port: 80"), 0o600)).To(Succeed())
+
+	app := NewApplication().(*airmidApplication)
+	c := &config{
+		resourceLocator: &localResourceLocator{
+			configDir: []string{dir},
+		},
+		ConfigExtensions: []string{".yml"},
+	}
+	g.Expect(c.loadProperty(ctx, app)).To(Succeed())
+
+	// The disabled watcher do nothing
+	w := &configWatcher{config: c, app: app}
+	w.Run(ctx)
+	w.Stop(ctx)
+
+	changedCh := make(chan []string, 1)
+	unsubscribe := Subscribe(app, func(_ context.Context, ev PropertiesChangedEvent) {
+		changedCh <- ev.Keys
+	})
+	defer unsubscribe()
+
+	// The change between loading and Run is reloaded too
+	g.Expect(os.WriteFile(filepath.Join(dir, "application.yml"), []byte("port: 81"), 0o600)).To(Succeed())
+	w = &configWatcher{
+		config:   c,
+		enabled:  true,
+		interval: 10 * time.Millisecond,
+		app:      app,
+	}
+	w.Run(ctx)
+
+	g.Eventually(changedCh).Should(Receive(Equal([]string{"port"})))
+	w.Stop(ctx)
+
+	v, err := app.Get(ctx, "port")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(v).To(Equal("81"))
+}
+
+func TestConfigWatcherInvalidDuration(t *testing.T) {
+	type testCase struct {
+		desp     string
+		bean     string
+		key      string
+		value    string
+		expected string
+	}
+	testCases := []testCase{
+		{
+			desp:     "zero config interval",
+			bean:     "airmid.app.config.watcher",
+			key:      "airmid.config.watch.interval",
+			value:    "0s",
+			expected: "must be at least 1ms",
+		},
+		{
+			desp:     "negative config debounce",
+			bean:     "airmid.app.config.watcher",
+			key:      "airmid.config.watch.debounce",
+			value:    "-1s",
+			expected: "must be at least 0s",
+		},
+		{
+			desp:     "zero secret interval",
+			bean:     "airmid.app.secret.watcher",
+			key:      "airmid.secret.watch.interval",
+			value:    "0s",
+			expected: "must be at least 1ms",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			app := NewApplication().(*airmidApplication)
+			g.Expect(app.registerAppBeanDefinitions()).To(Succeed())
+			g.Expect(app.Set(ctx, tc.key, tc.value)).To(Succeed())
+
+			_, err := app.GetBean(ctx, tc.bean)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+const (
+	// encryptKeyPropertyKey is the base64 encoded AES key to decrypt the 'ENC(...)' values,
+	// e.g. env 'AIRMID_CONFIG_ENCRYPT_KEY'.
+	encryptKeyPropertyKey = "airmid.config.encrypt.key"
+
+	// encryptKeyFilePropertyKey is the file which contain the base64 encoded AES key, it's the sibling
+	// of encryptKeyPropertyKey, so both of them can be written in YAML, e.g. env 'AIRMID_CONFIG_ENCRYPT_KEY_FILE'.
+	encryptKeyFilePropertyKey = "airmid.config.encrypt.key-file"
+
+	// aesGCMDecryptorName is the name of builtin decryptor.
+	aesGCMDecryptorName = "airmid.aes-gcm"
+)
+
+// registerDecryptor register the builtin AES-GCM decryptor if the key is specified by
+// 'airmid.config.encrypt.key' or 'airmid.config.encrypt.key-file'.
+func registerDecryptor(ctx context.Context, p props.Properties) error {
+	var key, keyFile string
+	_, err := p.Get(ctx, encryptKeyPropertyKey, props.WithDefault(""), props.WithTarget(&key))
+	if err != nil {
+		return xerrors.Wrapf(err, "Cannot get property '%v'", encryptKeyPropertyKey)
+	}
+	_, err = p.Get(ctx, encryptKeyFilePropertyKey, props.WithDefault(""), props.WithTarget(&keyFile))
+	if err != nil {
+		return xerrors.Wrapf(err, "Cannot get property '%v'", encryptKeyFilePropertyKey)
+	}
+
+	if key == "" && keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return xerrors.Wrapf(err, "Cannot read encrypt key file '%v'", keyFile)
+		}
+		key = strings.TrimSpace(string(data))
+	}
+	if key == "" {
+		return nil
+	}
+
+	rawKey, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return xerrors.Wrapf(err, "Invalid encrypt key, it must be base64 encoded")
+	}
+	d, err := props.NewAESGCMDecryptor(rawKey)
+	if err != nil {
+		return err
+	}
+	props.RegisterDecryptor(aesGCMDecryptorName, d)
+	return nil
+}
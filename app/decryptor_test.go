@@ -0,0 +1,104 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+func TestRegisterDecryptor(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+	keyFile := filepath.Join(t.TempDir(), "key")
+	NewWithT(t).Expect(os.WriteFile(keyFile, []byte(encodedKey+"\n"), 0o600)).To(Succeed())
+
+	type testCase struct {
+		desp  string
+		props map[string]string
+		err   string
+	}
+	testCases := []testCase{
+		{
+			desp:  "key",
+			props: map[string]string{encryptKeyPropertyKey: encodedKey},
+		},
+		{
+			desp:  "key file",
+			props: map[string]string{encryptKeyFilePropertyKey: keyFile},
+		},
+		{
+			desp:  "key file in yaml",
+			props: map[string]string{"airmid.config.encrypt.key-file": keyFile, encryptKeyPropertyKey: ""},
+		},
+		{
+			desp:  "key file in env",
+			props: map[string]string{"airmid.config.encrypt.key.file": keyFile},
+		},
+		{
+			desp:  "missing key file",
+			props: map[string]string{encryptKeyFilePropertyKey: filepath.Join(t.TempDir(), "missing")},
+			err:   "Cannot read encrypt key file",
+		},
+		{
+			desp:  "invalid key",
+			props: map[string]string{encryptKeyPropertyKey: "!!"},
+			err:   "Invalid encrypt key, it must be base64 encoded",
+		},
+		{
+			desp:  "invalid key size",
+			props: map[string]string{encryptKeyPropertyKey: base64.StdEncoding.EncodeToString([]byte("short"))},
+			err:   "Invalid AES key",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Cleanup(func() { props.UnregisterDecryptor(aesGCMDecryptorName) })
+
+			p := props.NewProperties()
+			for k, v := range tc.props {
+				g.Expect(p.Set(context.Background(), k, v)).To(Succeed())
+			}
+			value, err := props.EncryptAESGCM(key, "p1")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(p.Set(context.Background(), "db.password", value)).To(Succeed())
+
+			err = registerDecryptor(context.Background(), p)
+			if tc.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(MatchRegexp(tc.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			v, err := p.Get(context.Background(), "db.password")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(v).To(Equal("p1"))
+		})
+	}
+}
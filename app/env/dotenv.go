@@ -0,0 +1,197 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package env
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// DotenvEntry is the variable defined in dotenv file.
+type DotenvEntry struct {
+	// Key is the variable name
+	Key string
+	// Value is the unquoted & expanded value
+	Value string
+	// Line is the line number where the variable defined, start from 1
+	Line int
+}
+
+var dotenvKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// ParseDotenv parse the dotenv file with following rules:
+//  1. the line start with '#' and empty line is ignored
+//  2. the variable is defined as 'KEY=value' or 'export KEY=value'
+//  3. the single quoted value is kept as it is
+//  4. the double quoted value support the escapes '\n', '\r', '\t', '\"', '\\', '\$', and can span lines
+//  5. the unquoted value is trimmed, and the inline comment after whitespace is removed
+//  6. the '$KEY', '${KEY}' and '${KEY:-default}' in double quoted or unquoted value will be expanded
+//     unless it is escaped as '\$KEY',
+//     lookup is used to find the variable first, then the variable defined before in data.
+//     The undefined variable without default is kept as it is, e.g. the placeholder '${db.host}'.
+func ParseDotenv(data []byte, lookup func(key string) (string, bool)) ([]DotenvEntry, error) {
+	p := &dotenvParser{
+		lines:   strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"),
+		lookup:  lookup,
+		defined: map[string]string{},
+	}
+	return p.parse()
+}
+
+type dotenvParser struct {
+	lines   []string
+	lookup  func(key string) (string, bool)
+	defined map[string]string
+}
+
+func (p *dotenvParser) parse() ([]DotenvEntry, error) {
+	ret := []DotenvEntry{}
+	for i := 0; i < len(p.lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(p.lines[i])
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimSpace(rest)
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, xerrors.Errorf("Invalid line %d, it must be 'KEY=value'", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		if !dotenvKeyRegex.MatchString(key) {
+			return nil, xerrors.Errorf("Invalid key '%v' at line %d", key, lineNo)
+		}
+
+		value = strings.TrimLeft(value, " \t")
+		var err error
+		switch {
+		case strings.HasPrefix(value, "'"):
+			value, i, err = p.parseQuoted(value, i, '\'')
+		case strings.HasPrefix(value, "\""):
+			value, i, err = p.parseQuoted(value, i, '"')
+		default:
+			value = p.expand(stripInlineComment(value))
+		}
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "Invalid value of key '%v' at line %d", key, lineNo)
+		}
+
+		p.defined[key] = value
+		ret = append(ret, DotenvEntry{
+			Key:   key,
+			Value: value,
+			Line:  lineNo,
+		})
+	}
+	return ret, nil
+}
+
+// parseQuoted parse the quoted value which may span lines, it return the value and
+// the index of the last line consumed.
+func (p *dotenvParser) parseQuoted(value string, idx int, quote byte) (string, int, error) {
+	// seg hold the content since last escape, the variables in it will be expanded when flushed.
+	b, seg := strings.Builder{}, strings.Builder{}
+	flush := func() {
+		if quote == '"' {
+			b.WriteString(p.expand(seg.String()))
+		} else {
+			b.WriteString(seg.String())
+		}
+		seg.Reset()
+	}
+
+	s := value[1:]
+	for {
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			switch {
+			case c == quote:
+				if rest := strings.TrimSpace(s[i+1:]); rest != "" && rest[0] != '#' {
+					return "", idx, xerrors.Errorf("Unexpected content '%v' after quoted value", rest)
+				}
+				flush()
+				return b.String(), idx, nil
+			case c == '\\' && quote == '"' && i+1 < len(s):
+				flush()
+				i++
+				switch s[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(s[i])
+				}
+			default:
+				seg.WriteByte(c)
+			}
+		}
+
+		idx++
+		if idx >= len(p.lines) {
+			return "", idx, xerrors.Errorf("Unterminated quoted value")
+		}
+		seg.WriteByte('\n')
+		s = p.lines[idx]
+	}
+}
+
+func stripInlineComment(value string) string {
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			value = value[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(value)
+}
+
+var dotenvVarRegex = regexp.MustCompile(`\\?\$(?:\{([A-Za-z_][A-Za-z0-9_.]*)(?::-([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+func (p *dotenvParser) expand(value string) string {
+	return dotenvVarRegex.ReplaceAllStringFunc(value, func(s string) string {
+		if s[0] == '\\' {
+			return s[1:]
+		}
+
+		m := dotenvVarRegex.FindStringSubmatch(s)
+		key := m[1] + m[3]
+		if p.lookup != nil {
+			if v, ok := p.lookup(key); ok {
+				return v
+			}
+		}
+		if v, ok := p.defined[key]; ok {
+			return v
+		}
+		if strings.Contains(s, ":-") {
+			return m[2]
+		}
+		return s
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package env
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestParseDotenv(t *testing.T) {
+	type testCase struct {
+		desc     string
+		data     string
+		lookup   map[string]string
+		expected []DotenvEntry
+		errMsg   string
+	}
+
+	testCases := []testCase{
+		{
+			desc: "unquoted, export and comments",
+			data: `# comment
+
+export AIRMID_HOST = localhost # inline comment
+AIRMID_PORT=8080
+AIRMID_TAG=a#b
+AIRMID_EMPTY=
+`,
+			expected: []DotenvEntry{
+				{Key: "AIRMID_HOST", Value: "localhost", Line: 3},
+				{Key: "AIRMID_PORT", Value: "8080", Line: 4},
+				{Key: "AIRMID_TAG", Value: "a#b", Line: 5},
+				{Key: "AIRMID_EMPTY", Value: "", Line: 6},
+			},
+		},
+		{
+			desc: "quoted values",
+			data: "SINGLE='${HOME} \\n # x'\nDOUBLE=\"a\\tb\\\"c\\\\\" # comment\nMULTI=\"line1\nline2\"\nNEXT=v\n",
+			expected: []DotenvEntry{
+				{Key: "SINGLE", Value: "${HOME} \\n # x", Line: 1},
+				{Key: "DOUBLE", Value: "a\tb\"c\\", Line: 2},
+				{Key: "MULTI", Value: "line1\nline2", Line: 3},
+				{Key: "NEXT", Value: "v", Line: 5},
+			},
+		},
+		{
+			desc: "variable expansion",
+			data: `BASE=/opt
+DIR=${BASE}/app
+USER_DIR="$HOME/${NAME:-anonymous}"
+ESCAPED=\$BASE
+QUOTED_ESCAPED="\\$BASE \$BASE"
+UNDEFINED=${db.host}:$PORT/${EMPTY:-}
+`,
+			lookup: map[string]string{
+				"HOME": "/home/user",
+				"BASE": "/usr",
+			},
+			expected: []DotenvEntry{
+				{Key: "BASE", Value: "/opt", Line: 1},
+				{Key: "DIR", Value: "/usr/app", Line: 2},
+				{Key: "USER_DIR", Value: "/home/user/anonymous", Line: 3},
+				{Key: "ESCAPED", Value: "$BASE", Line: 4},
+				{Key: "QUOTED_ESCAPED", Value: "\\/usr $BASE", Line: 5},
+				{Key: "UNDEFINED", Value: "${db.host}:$PORT/", Line: 6},
+			},
+		},
+		{
+			desc:   "missing separator",
+			data:   "A=1\nB\n",
+			errMsg: "Invalid line 2, it must be 'KEY=value'",
+		},
+		{
+			desc:   "invalid key",
+			data:   "1A=1\n",
+			errMsg: "Invalid key '1A' at line 1",
+		},
+		{
+			desc:   "unterminated quote",
+			data:   "A=\"1\nB=2\n",
+			errMsg: "Invalid value of key 'A' at line 1: Unterminated quoted value",
+		},
+		{
+			desc:   "content after quote",
+			data:   "A='1' 2\n",
+			errMsg: "Invalid value of key 'A' at line 1: Unexpected content '2' after quoted value",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			entries, err := ParseDotenv([]byte(tc.data), func(key string) (string, bool) {
+				v, ok := tc.lookup[key]
+				return v, ok
+			})
+			if tc.errMsg != "" {
+				g.Expect(err).Should(gomega.HaveOccurred())
+				g.Expect(err.Error()).Should(gomega.Equal(tc.errMsg))
+				return
+			}
+			g.Expect(err).ShouldNot(gomega.HaveOccurred())
+			g.Expect(entries).Should(gomega.Equal(tc.expected))
+		})
+	}
+}
@@ -28,6 +28,8 @@ import (
 	"strings"
 
 	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/anyvoxel/airmid/ioc/props"
 )
 
 // Loader is a loader for loading environment variables.
@@ -43,6 +45,8 @@ type envLoader struct {
 	includePattern string
 	// excludePattern is a regex pattern for excluding env vars
 	excludePattern string
+	// environ return the env vars in the form of 'key=value'
+	environ func() []string
 
 	envs map[string]string
 }
@@ -56,13 +60,17 @@ type EnvLoaderOptions struct {
 	includePattern string
 	// excludePattern is a regex pattern for excluding env vars
 	excludePattern string
+	// environ return the env vars in the form of 'key=value', default is os.Environ
+	environ func() []string
 }
 
 // EnvLoaderOption ...
 // nolint
 type EnvLoaderOption func(options *EnvLoaderOptions)
 
-var defaultEnvLoaderOptions = EnvLoaderOptions{}
+var defaultEnvLoaderOptions = EnvLoaderOptions{
+	environ: os.Environ,
+}
 
 // NewEnvLoader returns a new loaderImpl instance which implements Loader.
 func NewEnvLoader(opts ...EnvLoaderOption) Loader {
@@ -74,6 +82,7 @@ func NewEnvLoader(opts ...EnvLoaderOption) Loader {
 		prefix:         options.prefix,
 		includePattern: options.includePattern,
 		excludePattern: options.excludePattern,
+		environ:        options.environ,
 		envs:           make(map[string]string),
 	}
 }
@@ -99,8 +108,15 @@ func WithEnvIncludePattern(includePattern string) EnvLoaderOption {
 	}
 }
 
+// WithEnviron is an option to set the env vars source, e.g. the vars read from dotenv file.
+func WithEnviron(environ func() []string) EnvLoaderOption {
+	return func(options *EnvLoaderOptions) {
+		options.environ = environ
+	}
+}
+
 func (l *envLoader) Load(ctx context.Context) map[string]string {
-	envStrs := os.Environ()
+	envStrs := l.environ()
 	for _, envStr := range envStrs {
 		kvArr := strings.SplitN(envStr, "=", 2)
 		if len(kvArr) != 2 {
@@ -122,11 +138,16 @@ func (l *envLoader) Load(ctx context.Context) map[string]string {
 		l.envs[key] = kvArr[1]
 	}
 
+	// The secret values must not be printed in logs
+	redacted := make(map[string]string, len(l.envs))
+	for k, v := range l.envs {
+		redacted[k] = props.Redact(k, v)
+	}
 	slogctx.FromCtx(ctx).DebugContext(
 		ctx,
 		"loading airmid environments variables",
 		slog.String("Prefix", l.prefix),
-		slog.Any("envs", l.envs),
+		slog.Any("envs", redacted),
 	)
 	return l.envs
 }
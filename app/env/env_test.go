@@ -88,6 +88,17 @@ func TestLoaderImpl_Load(t *testing.T) {
 				"TEST_INCLUDE": "test",
 			},
 		},
+		{
+			desc: "test environ",
+			newLoader: func() Loader {
+				return NewEnvLoader(WithPrefixOption("AIRMID_"), WithEnviron(func() []string {
+					return []string{"AIRMID_TEST_ENVIRON=test", "OTHER=test", "INVALID"}
+				}))
+			},
+			expected: map[string]string{
+				"TEST_ENVIRON": "test",
+			},
+		},
 	}
 
 	for _, c := range testCases {
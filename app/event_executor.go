@@ -0,0 +1,379 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	api "go.opentelemetry.io/otel/metric"
+
+	"github.com/anyvoxel/airmid/anvil"
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+const (
+	// EventOverflowPolicyBlock block the publisher until the queue of listener has free space.
+	EventOverflowPolicyBlock = "block"
+	// EventOverflowPolicyDropOldest drop the oldest queued event of listener to enqueue the new one.
+	EventOverflowPolicyDropOldest = "drop-oldest"
+	// EventOverflowPolicyDropNewest drop the new event if the queue of listener is full.
+	EventOverflowPolicyDropNewest = "drop-newest"
+	// EventOverflowPolicyError return error to the publisher if the queue of listener is full.
+	EventOverflowPolicyError = "error"
+)
+
+// eventExecutorConfig is the config of async event executor.
+type eventExecutorConfig struct {
+	// queueSize is the max number of queued events of each async listener
+	queueSize int `airmid:"value:${airmid.event.async.queue.size:=1024}"`
+	// overflowPolicy is the policy when the queue is full, it must be 'block', 'drop-oldest', 'drop-newest' or 'error'
+	overflowPolicy string `airmid:"value:${airmid.event.async.overflow.policy:=block}"`
+}
+
+// asyncEventSubmitter is implemented by the application which deliver the async events with executor.
+type asyncEventSubmitter interface {
+	submitAsyncEvent(ctx context.Context, listener any, name string, task func(context.Context)) error
+}
+
+// submitAsyncEvent submit the task of async listener, the task is executed with anvil.SafeRun. The
+// gpool is used if the application doesn't have the async event executor.
+func submitAsyncEvent(
+	ctx context.Context, app Application, listener any, name string, task func(context.Context)) error {
+	if s, ok := app.(asyncEventSubmitter); ok {
+		return s.submitAsyncEvent(ctx, listener, name, task)
+	}
+
+	return app.Submit(func() {
+		//nolint:contextcheck
+		anvil.SafeRun(ctx, task)
+	})
+}
+
+// errEventQueueRemoved is returned by pushing to the idle queue which is removed from executor.
+var errEventQueueRemoved = errors.New("EventQueueRemoved")
+
+// asyncEventExecutor deliver the events to async listeners with dedicated goroutines, each listener
+// has a bounded queue, and the events of same listener are handled in the submitted order.
+type asyncEventExecutor struct {
+	queueSize      int
+	overflowPolicy string
+
+	mu     sync.Mutex
+	queues map[any]*eventQueue
+	closed bool
+
+	dropped      api.Int64Counter
+	registration api.Registration
+}
+
+// newAsyncEventExecutor return the executor, and register the metrics of queue depth & dropped events.
+func newAsyncEventExecutor(queueSize int, overflowPolicy string) (*asyncEventExecutor, error) {
+	if queueSize <= 0 {
+		return nil, xerrors.Errorf("Invalid async event queue size '%v', it must be positive", queueSize)
+	}
+	switch overflowPolicy {
+	case EventOverflowPolicyBlock, EventOverflowPolicyDropOldest, EventOverflowPolicyDropNewest,
+		EventOverflowPolicyError:
+	default:
+		return nil, xerrors.Errorf("Invalid async event overflow policy '%v', it must be '%v', '%v', '%v' or '%v'",
+			overflowPolicy, EventOverflowPolicyBlock, EventOverflowPolicyDropOldest, EventOverflowPolicyDropNewest,
+			EventOverflowPolicyError)
+	}
+
+	e := &asyncEventExecutor{
+		queueSize:      queueSize,
+		overflowPolicy: overflowPolicy,
+		queues:         map[any]*eventQueue{},
+	}
+	if err := e.initMetrics(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *asyncEventExecutor) initMetrics() error {
+	m := otel.Meter(anvil.AirmidPackageName, api.WithInstrumentationVersion(anvil.AirmidPackageVersion))
+
+	depth, err := m.Int64ObservableGauge(
+		"event_async_queue_depth",
+		api.WithDescription("It's the number of events which are waiting be handled by async listener"),
+	)
+	if err != nil {
+		return err
+	}
+
+	// The callback is unregistered when executor is closed, so the executor can be garbage collected.
+	e.registration, err = m.RegisterCallback(func(_ context.Context, o api.Observer) error {
+		for _, q := range e.allQueues() {
+			o.ObserveInt64(depth, int64(q.depth()), api.WithAttributes(attribute.String("listener", q.name)))
+		}
+		return nil
+	}, depth)
+	if err != nil {
+		return err
+	}
+
+	e.dropped, err = m.Int64Counter(
+		"event_async_dropped_total",
+		api.WithDescription("It's the number of events which are dropped because the queue of async listener is full"),
+	)
+	return err
+}
+
+func (e *asyncEventExecutor) allQueues() []*eventQueue {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ret := make([]*eventQueue, 0, len(e.queues))
+	for _, q := range e.queues {
+		ret = append(ret, q)
+	}
+	return ret
+}
+
+// queue return the queue of listener, it's created on first use and removed when it's idle. It
+// return nil if executor is closed.
+func (e *asyncEventExecutor) queue(listener any, name string) *eventQueue {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return nil
+	}
+	q, ok := e.queues[listener]
+	if !ok {
+		q = newEventQueue(name, e.queueSize, func(q *eventQueue) {
+			e.removeIdle(listener, q)
+		})
+		e.queues[listener] = q
+	}
+	return q
+}
+
+// removeIdle remove the queue of listener if it's still idle, so the queue of unsubscribed listener
+// isn't kept forever. The queue is created again when the listener is submitted later.
+func (e *asyncEventExecutor) removeIdle(listener any, q *eventQueue) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.running || len(q.tasks) > 0 || q.closed || e.queues[listener] != q {
+		return
+	}
+	delete(e.queues, listener)
+	q.removed = true
+}
+
+// Submit enqueue the task of listener, which is handled by the overflow policy if the queue is full.
+func (e *asyncEventExecutor) Submit(ctx context.Context, listener any, name string, task func(context.Context)) error {
+	var dropped bool
+	var err error
+	for {
+		q := e.queue(listener, name)
+		if q == nil {
+			return xerrors.Errorf("Async event executor is closed, the event of listener '%v' is rejected", name)
+		}
+		// The queue may be removed after it's got, so the task is pushed to the new one
+		dropped, err = q.push(eventTask{ctx: ctx, fn: task}, e.overflowPolicy)
+		if !errors.Is(err, errEventQueueRemoved) {
+			break
+		}
+	}
+	if dropped {
+		e.dropped.Add(ctx, 1, api.WithAttributes(
+			attribute.String("listener", name),
+			attribute.String("policy", e.overflowPolicy),
+		))
+	}
+	return err
+}
+
+// Close stop accepting the events, and wait until the queued events are handled or ctx is done.
+// The publishers blocked by the full queue are woken with error.
+func (e *asyncEventExecutor) Close(ctx context.Context) error {
+	e.mu.Lock()
+	e.closed = true
+	e.mu.Unlock()
+
+	err := e.registration.Unregister()
+	queues := e.allQueues()
+	drained := make([]<-chan struct{}, 0, len(queues))
+	for _, q := range queues {
+		drained = append(drained, q.close())
+	}
+	for i, q := range queues {
+		select {
+		case <-drained[i]:
+		case <-ctx.Done():
+			return xerrors.Wrapf(ctx.Err(), "Async event queue of listener '%v' isn't drained", q.name)
+		}
+	}
+	return err
+}
+
+type eventTask struct {
+	ctx context.Context //nolint:containedctx
+	fn  func(context.Context)
+}
+
+// eventQueueContextKey is the key of queue in the context of its tasks.
+type eventQueueContextKey struct{}
+
+// eventQueue is the bounded queue of listener, the tasks are executed one by one in a goroutine,
+// which is started when task is pushed and exited when the queue is empty.
+type eventQueue struct {
+	name string
+	size int
+
+	mu sync.Mutex
+	// notFull is closed and replaced when a task is popped, to wake the blocked publishers
+	notFull chan struct{}
+	tasks   []eventTask
+	running bool
+	closed  bool
+	// drained is closed when the queue is closed and the tasks are all executed
+	drained chan struct{}
+
+	// onIdle is called when the tasks are all executed, and removed is set if the queue is removed
+	onIdle  func(q *eventQueue)
+	removed bool
+}
+
+func newEventQueue(name string, size int, onIdle func(q *eventQueue)) *eventQueue {
+	return &eventQueue{
+		name:    name,
+		size:    size,
+		notFull: make(chan struct{}),
+		tasks:   make([]eventTask, 0, size),
+		onIdle:  onIdle,
+	}
+}
+
+func (q *eventQueue) depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.tasks)
+}
+
+// push enqueue the task, it return whether a task is dropped by the overflow policy. The block policy
+// wait until the queue has free space or the ctx of task is done, but the task published by the listener
+// of queue is enqueued without waiting, since the queue is only drained by the listener itself.
+func (q *eventQueue) push(task eventTask, policy string) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.removed {
+		return false, errEventQueueRemoved
+	}
+	bypass := policy == EventOverflowPolicyBlock && task.ctx.Value(eventQueueContextKey{}) == q
+	dropped := false
+	for !q.closed && !bypass && len(q.tasks) >= q.size {
+		switch policy {
+		case EventOverflowPolicyDropOldest:
+			q.tasks[0] = eventTask{}
+			q.tasks = q.tasks[1:]
+			dropped = true
+		case EventOverflowPolicyDropNewest:
+			return true, nil
+		case EventOverflowPolicyError:
+			return false, xerrors.Errorf("Async event queue of listener '%v' is full", q.name)
+		default:
+			if err := q.waitNotFull(task.ctx); err != nil {
+				return false, err
+			}
+		}
+	}
+	if q.closed {
+		return false, xerrors.Errorf("Async event queue of listener '%v' is closed", q.name)
+	}
+
+	q.tasks = append(q.tasks, task)
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+	return dropped, nil
+}
+
+// waitNotFull wait until a task is popped, the queue is closed or ctx is done, the q.mu must be held.
+func (q *eventQueue) waitNotFull(ctx context.Context) error {
+	notFull := q.notFull
+	q.mu.Unlock()
+	defer q.mu.Lock()
+
+	select {
+	case <-notFull:
+		return nil
+	case <-ctx.Done():
+		return xerrors.Wrapf(ctx.Err(), "Async event queue of listener '%v' is full", q.name)
+	}
+}
+
+// wakeWaiters wake the publishers which are blocked by waitNotFull, the q.mu must be held.
+func (q *eventQueue) wakeWaiters() {
+	close(q.notFull)
+	q.notFull = make(chan struct{})
+}
+
+// close stop accepting the tasks, it return the channel which is closed when the queued tasks are executed.
+func (q *eventQueue) close() <-chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		q.drained = make(chan struct{})
+		q.wakeWaiters()
+		if !q.running {
+			close(q.drained)
+		}
+	}
+	return q.drained
+}
+
+// run execute the queued tasks in order until the queue is empty, the queue is carried by the context
+// of tasks, so the events published by the listener won't block on its own queue.
+func (q *eventQueue) run() {
+	for {
+		q.mu.Lock()
+		if len(q.tasks) == 0 {
+			q.running = false
+			if q.closed {
+				close(q.drained)
+			}
+			q.mu.Unlock()
+			q.onIdle(q)
+			return
+		}
+		task := q.tasks[0]
+		q.tasks[0] = eventTask{}
+		q.tasks = q.tasks[1:]
+		q.wakeWaiters()
+		q.mu.Unlock()
+
+		anvil.SafeRun(context.WithValue(task.ctx, eventQueueContextKey{}, q), task.fn)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/anyvoxel/airmid/ioc"
+)
+
+type eventExecutorStartupHandler struct{}
+
+var (
+	_ ApplicationStartupHandler = (*eventExecutorStartupHandler)(nil)
+)
+
+func (*eventExecutorStartupHandler) Name() string {
+	return "EventExecutorStartupHandler"
+}
+
+func (*eventExecutorStartupHandler) BeforeLoadProps(_ context.Context, app *airmidApplication, _ *option) error {
+	return app.RegisterBeanDefinition(
+		"airmid.event.executor.config",
+		ioc.MustNewBeanDefinition(
+			reflect.TypeOf((*eventExecutorConfig)(nil)),
+			ioc.WithLazyMode(),
+		),
+	)
+}
+
+func (*eventExecutorStartupHandler) AfterLoadProps(ctx context.Context, app *airmidApplication, _ *option) error {
+	c, err := ioc.GetBean[*eventExecutorConfig](ctx, app, "airmid.event.executor.config")
+	if err != nil {
+		return err
+	}
+
+	executor, err := newAsyncEventExecutor(c.queueSize, c.overflowPolicy)
+	if err != nil {
+		return err
+	}
+
+	app.eventExecutor = executor
+	return nil
+}
+
+func (*eventExecutorStartupHandler) BeforeStartRunner(_ context.Context, _ *airmidApplication, _ *option) error {
+	return nil
+}
@@ -0,0 +1,360 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/panjf2000/ants/v2"
+)
+
+func TestNewAsyncEventExecutor(t *testing.T) {
+	type testCase struct {
+		desp     string
+		size     int
+		policy   string
+		expected string
+	}
+	testCases := []testCase{
+		{
+			desp:     "valid config",
+			size:     1,
+			policy:   EventOverflowPolicyDropOldest,
+			expected: "",
+		},
+		{
+			desp:     "invalid size",
+			size:     0,
+			policy:   EventOverflowPolicyBlock,
+			expected: "Invalid async event queue size '0', it must be positive",
+		},
+		{
+			desp:   "invalid policy",
+			size:   1,
+			policy: "panic",
+			expected: "Invalid async event overflow policy 'panic', " +
+				"it must be 'block', 'drop-oldest', 'drop-newest' or 'error'",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			e, err := newAsyncEventExecutor(tc.size, tc.policy)
+			if tc.expected == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(e).ToNot(BeNil())
+				g.Expect(e.Close(context.Background())).To(Succeed())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(Equal(tc.expected))
+		})
+	}
+}
+
+// submitBlocked submit the task which block the queue of listener until the returned function is called.
+func submitBlocked(g *WithT, e *asyncEventExecutor, listener any) func() {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	err := e.Submit(context.Background(), listener, "l", func(context.Context) {
+		close(started)
+		<-release
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	<-started
+	return func() {
+		close(release)
+	}
+}
+
+func TestAsyncEventExecutorOverflowPolicy(t *testing.T) {
+	type testCase struct {
+		desp     string
+		policy   string
+		handled  []int
+		errCount int
+	}
+	testCases := []testCase{
+		{
+			desp:    "drop oldest",
+			policy:  EventOverflowPolicyDropOldest,
+			handled: []int{3, 4},
+		},
+		{
+			desp:    "drop newest",
+			policy:  EventOverflowPolicyDropNewest,
+			handled: []int{1, 2},
+		},
+		{
+			desp:     "error",
+			policy:   EventOverflowPolicyError,
+			handled:  []int{1, 2},
+			errCount: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			e, err := newAsyncEventExecutor(2, tc.policy)
+			g.Expect(err).ToNot(HaveOccurred())
+			defer func() {
+				g.Expect(e.Close(context.Background())).To(Succeed())
+			}()
+			listener := new(int)
+			release := submitBlocked(g, e, listener)
+
+			var mu sync.Mutex
+			var wg sync.WaitGroup
+			handled := []int{}
+			errCount := 0
+			for i := 1; i <= 4; i++ {
+				wg.Add(1)
+				err := e.Submit(context.Background(), listener, "l", func(context.Context) {
+					defer wg.Done()
+					mu.Lock()
+					handled = append(handled, i)
+					mu.Unlock()
+				})
+				if err != nil {
+					wg.Done()
+					errCount++
+				}
+			}
+			q := e.queue(listener, "l")
+			g.Expect(q.depth()).To(Equal(2))
+
+			release()
+			g.Eventually(q.depth).Should(BeZero())
+			if tc.errCount > 0 {
+				wg.Wait()
+			}
+			g.Eventually(func() []int {
+				mu.Lock()
+				defer mu.Unlock()
+				return append([]int{}, handled...)
+			}).Should(Equal(tc.handled))
+			g.Expect(errCount).To(Equal(tc.errCount))
+		})
+	}
+}
+
+func TestAsyncEventExecutorBlockPolicy(t *testing.T) {
+	g := NewWithT(t)
+	e, err := newAsyncEventExecutor(1, EventOverflowPolicyBlock)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer func() {
+		g.Expect(e.Close(context.Background())).To(Succeed())
+	}()
+	listener := new(int)
+	other := new(int)
+	release := submitBlocked(g, e, listener)
+
+	var mu sync.Mutex
+	handled := []int{}
+	record := func(i int) func(context.Context) {
+		return func(context.Context) {
+			mu.Lock()
+			handled = append(handled, i)
+			mu.Unlock()
+		}
+	}
+	g.Expect(e.Submit(context.Background(), listener, "l", record(1))).To(Succeed())
+
+	submitted := make(chan struct{})
+	go func() {
+		defer close(submitted)
+		g.Expect(e.Submit(context.Background(), listener, "l", record(2))).To(Succeed())
+	}()
+	// The blocked listener doesn't affect others
+	done := make(chan struct{})
+	g.Expect(e.Submit(context.Background(), other, "other", func(context.Context) {
+		close(done)
+	})).To(Succeed())
+	g.Eventually(done).Should(BeClosed())
+	g.Consistently(submitted).ShouldNot(BeClosed())
+
+	release()
+	g.Eventually(submitted).Should(BeClosed())
+	g.Eventually(func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int{}, handled...)
+	}).Should(Equal([]int{1, 2}))
+}
+
+func TestAsyncEventExecutorBlockPolicyContext(t *testing.T) {
+	g := NewWithT(t)
+	e, err := newAsyncEventExecutor(1, EventOverflowPolicyBlock)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer func() {
+		g.Expect(e.Close(context.Background())).To(Succeed())
+	}()
+	listener := new(int)
+	release := submitBlocked(g, e, listener)
+	defer release()
+	g.Expect(e.Submit(context.Background(), listener, "l", func(context.Context) {})).To(Succeed())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = e.Submit(ctx, listener, "l", func(context.Context) {})
+	g.Expect(err).To(MatchError(context.DeadlineExceeded))
+	g.Expect(err.Error()).To(ContainSubstring("Async event queue of listener 'l' is full"))
+}
+
+func TestAsyncEventExecutorReentrantSubmit(t *testing.T) {
+	g := NewWithT(t)
+	e, err := newAsyncEventExecutor(1, EventOverflowPolicyBlock)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer func() {
+		g.Expect(e.Close(context.Background())).To(Succeed())
+	}()
+	listener := new(int)
+
+	var mu sync.Mutex
+	handled := []int{}
+	record := func(i int) func(context.Context) {
+		return func(context.Context) {
+			mu.Lock()
+			handled = append(handled, i)
+			mu.Unlock()
+		}
+	}
+	// The listener publish events to itself, which would block forever if it wait the full queue
+	g.Expect(e.Submit(context.Background(), listener, "l", func(ctx context.Context) {
+		g.Expect(e.Submit(ctx, listener, "l", record(1))).To(Succeed())
+		g.Expect(e.Submit(ctx, listener, "l", record(2))).To(Succeed())
+	})).To(Succeed())
+	g.Eventually(func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int{}, handled...)
+	}).Should(Equal([]int{1, 2}))
+}
+
+func TestAsyncEventExecutorRemoveIdleQueue(t *testing.T) {
+	g := NewWithT(t)
+	e, err := newAsyncEventExecutor(1, EventOverflowPolicyBlock)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer func() {
+		g.Expect(e.Close(context.Background())).To(Succeed())
+	}()
+
+	// The queues of listeners are removed when they are idle, e.g. the listeners are unsubscribed
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		g.Expect(e.Submit(context.Background(), new(int), "l", func(context.Context) {
+			wg.Done()
+		})).To(Succeed())
+	}
+	wg.Wait()
+	g.Eventually(e.allQueues).Should(BeEmpty())
+
+	listener := new(int)
+	release := submitBlocked(g, e, listener)
+	g.Expect(e.allQueues()).To(HaveLen(1))
+	release()
+	g.Eventually(e.allQueues).Should(BeEmpty())
+
+	// The removed queue is created again
+	done := make(chan struct{})
+	g.Expect(e.Submit(context.Background(), listener, "l", func(context.Context) {
+		close(done)
+	})).To(Succeed())
+	g.Eventually(done).Should(BeClosed())
+}
+
+func TestAsyncEventExecutorClose(t *testing.T) {
+	g := NewWithT(t)
+	e, err := newAsyncEventExecutor(1, EventOverflowPolicyBlock)
+	g.Expect(err).ToNot(HaveOccurred())
+	listener := new(int)
+	release := submitBlocked(g, e, listener)
+
+	handled := make(chan struct{})
+	g.Expect(e.Submit(context.Background(), listener, "l", func(context.Context) {
+		close(handled)
+	})).To(Succeed())
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- e.Submit(context.Background(), listener, "l", func(context.Context) {})
+	}()
+	g.Consistently(blocked).ShouldNot(Receive())
+
+	// The blocked publisher is woken, and the queued event is kept until timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	g.Expect(e.Close(ctx)).To(MatchError(context.DeadlineExceeded))
+	g.Eventually(blocked).Should(Receive(MatchError("Async event queue of listener 'l' is closed")))
+	g.Expect(e.Submit(context.Background(), listener, "l", func(context.Context) {})).To(
+		MatchError("Async event executor is closed, the event of listener 'l' is rejected"))
+
+	release()
+	g.Expect(e.Close(context.Background())).To(Succeed())
+	g.Expect(handled).To(BeClosed())
+}
+
+func TestAsyncEventExecutorOrder(t *testing.T) {
+	g := NewWithT(t)
+	e, err := newAsyncEventExecutor(1024, EventOverflowPolicyBlock)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer func() {
+		g.Expect(e.Close(context.Background())).To(Succeed())
+	}()
+
+	var wg sync.WaitGroup
+	handled := []int{}
+	for i := 0; i < 1000; i++ {
+		wg.Add(1)
+		g.Expect(e.Submit(context.Background(), e, "l", func(context.Context) {
+			defer wg.Done()
+			handled = append(handled, i)
+			if i%100 == 0 {
+				panic(i)
+			}
+		})).To(Succeed())
+	}
+	wg.Wait()
+	g.Expect(handled).To(HaveLen(1000))
+	for i, v := range handled {
+		g.Expect(v).To(Equal(i))
+	}
+}
+
+func TestSubmitAsyncEventWithGPool(t *testing.T) {
+	g := NewWithT(t)
+	p, err := ants.NewPool(1, ants.WithNonblocking(true))
+	g.Expect(err).ToNot(HaveOccurred())
+	defer p.Release()
+	app := &airmidApplication{gpool: p}
+
+	release := make(chan struct{})
+	g.Expect(app.submitAsyncEvent(context.Background(), app, "l", func(context.Context) {
+		<-release
+	})).To(Succeed())
+	// The full pool return error instead of panic
+	err = app.submitAsyncEvent(context.Background(), app, "l", func(context.Context) {})
+	g.Expect(err).To(HaveOccurred())
+	close(release)
+}
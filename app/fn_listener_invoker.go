@@ -21,10 +21,12 @@ package app
 
 import (
 	"context"
+	"log/slog"
 	"reflect"
 	"strings"
 
-	"github.com/anyvoxel/airmid/anvil"
+	slogctx "github.com/veqryn/slog-context"
+
 	"github.com/anyvoxel/airmid/anvil/xerrors"
 	"github.com/anyvoxel/airmid/anvil/xreflect"
 )
@@ -39,7 +41,7 @@ type fnListenerInvoker struct {
 }
 
 // Invoke implement ListenerInvoker.Invoke.
-func (i *fnListenerInvoker) Invoke(ctx context.Context, event ApplicationEvent) {
+func (i *fnListenerInvoker) Invoke(ctx context.Context, event ApplicationEvent) error {
 	in1 := reflect.ValueOf(event)
 	if event == nil {
 		// If the event is nil, we must construct it's zero value.
@@ -50,25 +52,40 @@ func (i *fnListenerInvoker) Invoke(ctx context.Context, event ApplicationEvent)
 		in1 = reflect.New(i.arg1).Elem()
 	}
 
-	if in1.Type().AssignableTo(i.arg1) {
-		if !i.async {
-			i.fn.Call([]reflect.Value{
-				reflect.ValueOf(ctx),
-				in1,
-			})
-			return
+	if !i.supportsEventType(in1.Type()) {
+		return nil
+	}
+
+	if !i.async {
+		return i.call(ctx, in1)
+	}
+
+	return submitAsyncEvent(ctx, i.app, i, i.name, func(ctx context.Context) {
+		if err := i.call(ctx, in1); err != nil {
+			slogctx.FromCtx(ctx).ErrorContext(
+				ctx, "Async listener failed to handle event",
+				slog.String("Listener", i.name),
+				slog.Any("Error", err),
+			)
 		}
+	})
+}
+
+// supportsEventType implement eventTypeMatcher, the nil event is handled as zero value of arg1.
+func (i *fnListenerInvoker) supportsEventType(typ reflect.Type) bool {
+	return typ == nil || typ.AssignableTo(i.arg1)
+}
 
-		anvil.Must(i.app.Submit(func() {
-			//nolint:contextcheck
-			anvil.SafeRun(ctx, func(ctx context.Context) {
-				i.fn.Call([]reflect.Value{
-					reflect.ValueOf(ctx),
-					in1,
-				})
-			})
-		}))
+// call invoke fn with event, and return the error if fn return it.
+func (i *fnListenerInvoker) call(ctx context.Context, in1 reflect.Value) error {
+	out := i.fn.Call([]reflect.Value{
+		reflect.ValueOf(ctx),
+		in1,
+	})
+	if len(out) == 0 || out[0].IsNil() {
+		return nil
 	}
+	return out[0].Interface().(error) //nolint:forcetypeassert
 }
 
 // NewFnListenerInvoker will return fn ListenerInvoker impl.
@@ -79,9 +96,10 @@ func NewFnListenerInvoker(fn reflect.Value, name string, app Application) (Liste
 		return nil, xerrors.WrapContinue("type '%s' of object '%s' doesn't match kind func", typ.String(), name)
 	}
 
-	if typ.NumIn() != 2 || typ.NumOut() != 0 {
+	if typ.NumIn() != 2 || typ.NumOut() > 1 || (typ.NumOut() == 1 && typ.Out(0) != xreflect.ErrorType) {
 		return nil, xerrors.WrapContinue(
-			"func '%s' expect in '%d' and out '%d', got in '%d' and out '%d'", name, 2, 0, typ.NumIn(), typ.NumOut(),
+			"func '%s' expect in '%d' and out '%d' or 'error', got in '%d' and out '%d'",
+			name, 2, 0, typ.NumIn(), typ.NumOut(),
 		)
 	}
 
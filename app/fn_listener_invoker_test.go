@@ -93,8 +93,13 @@ func (l *testFnEventListener) On1(ctx context.Context) {
 
 }
 
-func (l *testFnEventListener) On2(ctx context.Context, event *testFnEventListenerEvent) error {
-	return nil
+func (l *testFnEventListener) OnEvent2WithError(ctx context.Context, event *testFnEventListenerEvent) error {
+	atomic.AddInt32(l.count, 4)
+	return xerrors.Errorf("OnEvent2WithError failed")
+}
+
+func (l *testFnEventListener) On2(ctx context.Context, event *testFnEventListenerEvent) int {
+	return 0
 }
 
 func (l *testFnEventListener) On1Type(int, ApplicationEvent) {
@@ -136,8 +141,16 @@ func TestNewFnListenerInvoker(t *testing.T) {
 	g.Expect(atomic.LoadInt32(&count)).To(Equal(int32(3)))
 	vobj, err = NewFnListenerInvoker(reflect.ValueOf(obj).MethodByName("OnEvent2"), "OnEvent2", app)
 	g.Expect(err).ToNot(HaveOccurred())
-	vobj.Invoke(context.Background(), nil)
+	g.Expect(vobj.Invoke(context.Background(), nil)).To(Succeed())
 	g.Expect(atomic.LoadInt32(&count)).To(Equal(int32(6)))
+	vobj, err = NewFnListenerInvoker(reflect.ValueOf(obj).MethodByName("OnEvent2WithError"), "OnEvent2WithError", app)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = vobj.Invoke(context.Background(), nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(Equal("OnEvent2WithError failed"))
+	g.Expect(atomic.LoadInt32(&count)).To(Equal(int32(10)))
+	g.Expect(vobj.Invoke(context.Background(), NewDefaultApplicationEvent(nil))).To(Succeed())
+	g.Expect(atomic.LoadInt32(&count)).To(Equal(int32(10)))
 
 	vobj, err = NewFnListenerInvoker(reflect.ValueOf(t), "o1", app)
 	g.Expect(err).To(HaveOccurred())
@@ -147,13 +160,13 @@ func TestNewFnListenerInvoker(t *testing.T) {
 
 	vobj, err = NewFnListenerInvoker(reflect.ValueOf(obj).MethodByName("On1"), "On1", app)
 	g.Expect(err).To(HaveOccurred())
-	g.Expect(err.Error()).To(MatchRegexp(`func 'On1' expect in '2' and out '0', got in '1' and out '0': Continue`))
+	g.Expect(err.Error()).To(MatchRegexp(`func 'On1' expect in '2' and out '0' or 'error', got in '1' and out '0': Continue`))
 	g.Expect(xerrors.IsContinue(err)).To(BeTrue())
 	g.Expect(vobj).To(BeNil())
 
 	vobj, err = NewFnListenerInvoker(reflect.ValueOf(obj).MethodByName("On2"), "On2", app)
 	g.Expect(err).To(HaveOccurred())
-	g.Expect(err.Error()).To(MatchRegexp(`func 'On2' expect in '2' and out '0', got in '2' and out '1': Continue`))
+	g.Expect(err.Error()).To(MatchRegexp(`func 'On2' expect in '2' and out '0' or 'error', got in '2' and out '1': Continue`))
 	g.Expect(xerrors.IsContinue(err)).To(BeTrue())
 	g.Expect(vobj).To(BeNil())
 
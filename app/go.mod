@@ -3,6 +3,7 @@ module github.com/anyvoxel/airmid/app
 go 1.25.3
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/agiledragon/gomonkey/v2 v2.13.0
 	github.com/anyvoxel/airmid/anvil v0.1.2
 	github.com/anyvoxel/airmid/ioc v0.1.2
@@ -14,7 +15,7 @@ require (
 	go.opentelemetry.io/otel/sdk/metric v1.38.0
 	go.uber.org/automaxprocs v1.6.0
 	go.uber.org/mock v0.6.0
-	gopkg.in/yaml.v2 v2.4.0
+	go.yaml.in/yaml/v3 v3.0.4
 )
 
 require (
@@ -26,7 +27,6 @@ require (
 	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
 	go.opentelemetry.io/otel/sdk v1.38.0 // indirect
 	go.opentelemetry.io/otel/trace v1.38.0 // indirect
-	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/net v0.43.0 // indirect
 	golang.org/x/sync v0.18.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
@@ -0,0 +1,184 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/anyvoxel/airmid/anvil/conv"
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+	"github.com/anyvoxel/airmid/ioc"
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+const (
+	// helpPropertyKey is the property to print the help and exit, the '--help' flag is mapped to it.
+	helpPropertyKey = "airmid.help"
+)
+
+func (a *airmidApplication) isHelpRequested(ctx context.Context) (bool, error) {
+	var help bool
+	_, err := a.Get(ctx, helpPropertyKey, props.WithDefault("false"), props.WithTarget(&help))
+	if err != nil {
+		return false, xerrors.Wrapf(err, "Cannot get property '%v'", helpPropertyKey)
+	}
+	return help, nil
+}
+
+// printHelp print all of properties consumed by registered beans, with the type, default,
+// current value & source, the bean field which consume it and the description.
+func (a *airmidApplication) printHelp(ctx context.Context, w io.Writer) error {
+	if w == nil {
+		w = os.Stdout
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Usage of %v:\n", filepath.Base(os.Args[0]))
+	fmt.Fprintf(tw, "  The property can be set by '--key=value', env 'AIRMID_KEY' or config files.\n\n")
+	fmt.Fprintln(tw, "KEY\tTYPE\tDEFAULT\tVALUE\tSOURCE\tCONSUMER\tDESCRIPTION")
+	for _, d := range ioc.PropertyDescriptors(a) {
+		def := "-"
+		if d.Default != nil {
+			def = quoteEmpty(props.Redact(d.Key, *d.Default))
+		}
+		value, source := a.describeProperty(ctx, d)
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v.%v\t%v\n",
+			d.Key, d.Typ.String(), def, quoteEmpty(value), source, d.BeanName, d.FieldName, d.Description)
+	}
+	return tw.Flush()
+}
+
+// describeProperty return the current resolved value & source of property.
+func (a *airmidApplication) describeProperty(ctx context.Context, d ioc.PropertyDescriptor) (string, string) {
+	if d.Config {
+		return "-", "-"
+	}
+
+	opts := []props.GetOption{}
+	if d.Default != nil {
+		opts = append(opts, props.WithDefault(*d.Default))
+	}
+
+	value := "<unset>"
+	typ := d.Typ
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	// The composite value (e.g. map or slice) is read from nested or indexed keys into the field type
+	keys := []string{d.Key}
+	if isCompositeType(typ) {
+		target := reflect.New(typ)
+		if _, err := a.Get(ctx, d.Key, append(opts, props.WithTarget(target.Interface()))...); err == nil {
+			value = formatValue(target.Elem())
+		}
+		keys = append(keys, a.Keys(d.Key)...)
+	} else if v, err := a.Get(ctx, d.Key, opts...); err == nil {
+		value = v.(string)
+	}
+
+	for _, k := range keys {
+		if props.IsSecretKey(k) || a.isEncrypted(k) {
+			value = props.RedactedValue
+			break
+		}
+	}
+
+	for _, k := range keys {
+		if origin, err := a.Origin(ctx, k); err == nil {
+			return value, origin.String()
+		}
+	}
+	if d.Default != nil {
+		return value, "default"
+	}
+	return value, "-"
+}
+
+// isCompositeType return true if the typ is read from nested or indexed keys instead of a string value.
+//
+//nolint:exhaustive
+func isCompositeType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return !conv.HasConverter(typ)
+	default:
+		return false
+	}
+}
+
+// formatValue return the readable value, the elements of slice are joined with ',' and the entries
+// of map are sorted and joined as 'k1=v1,k2=v2'.
+//
+//nolint:exhaustive
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if conv.HasToString(v.Type()) {
+			break
+		}
+		ret := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			ret = append(ret, formatValue(v.Index(i)))
+		}
+		return strings.Join(ret, ",")
+	case reflect.Map:
+		if conv.HasToString(v.Type()) {
+			break
+		}
+		ret := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			ret = append(ret, formatValue(iter.Key())+"="+formatValue(iter.Value()))
+		}
+		sort.Strings(ret)
+		return strings.Join(ret, ",")
+	}
+
+	if s, err := conv.ToString(v.Interface()); err == nil {
+		return s
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// isEncrypted return true if the raw value of key is encrypted, so it must be redacted after decrypted.
+func (a *airmidApplication) isEncrypted(key string) bool {
+	for _, s := range a.PropertySources() {
+		if v, ok := s.Lookup(key); ok {
+			return props.IsEncrypted(v)
+		}
+	}
+	return false
+}
+
+// quoteEmpty return '""' for empty string, so the column won't be blank.
+func quoteEmpty(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return s
+}
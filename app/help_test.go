@@ -0,0 +1,97 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/anyvoxel/airmid/ioc"
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+type testHelpBean struct {
+	port    int               `airmid:"value:${test.help.port:=8080}" desc:"the listen port"`
+	hosts   []string          `airmid:"value:${test.help.hosts:=a,b}"`
+	timeout time.Duration     `airmid:"value:${test.help.timeout}"`
+	name    string            `airmid:"value:${test.help.name}"`
+	secret  string            `airmid:"value:${test.help.secret:=s}"`
+	license string            `airmid:"value:${test.help.license}"`
+	cert    string            `airmid:"value:${test.help.clientCert}"`
+	labels  map[string]string `airmid:"value:${test.help.labels}"`
+}
+
+func TestRunWithHelp(t *testing.T) {
+	g := NewWithT(t)
+	t.Setenv("AIRMID_INCLUDE_ENV_PATTERNS", "")
+	t.Setenv("AIRMID_EXCLUDE_ENV_PATTERNS", "")
+	t.Setenv("AIRMID_TEST_HELP_TIMEOUT", "3s")
+
+	key := []byte("0123456789abcdef")
+	license, err := props.EncryptAESGCM(key, "l1")
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Setenv("AIRMID_CONFIG_ENCRYPT_KEY", base64.StdEncoding.EncodeToString(key))
+	t.Setenv("AIRMID_TEST_HELP_LICENSE", license)
+	// The encrypted value is found in relaxed binding
+	t.Setenv("AIRMID_TEST_HELP_CLIENT_CERT", license)
+	t.Cleanup(func() { props.UnregisterDecryptor(aesGCMDecryptorName) })
+
+	a := NewApplication()
+	g.Expect(a.RegisterBeanDefinition("testHelpBean", ioc.MustNewBeanDefinition(
+		reflect.TypeOf((*testHelpBean)(nil)),
+	))).To(Succeed())
+
+	buf := &bytes.Buffer{}
+	err = a.Run(context.Background(), WithArgs("--help", "--test.help.hosts=x,y", "--test.help.labels.b=2", "--test.help.labels.a=1"), WithHelpOutput(buf))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The columns are padded with at least 2 spaces
+	sep := regexp.MustCompile(`\s{2,}`)
+	rows := map[string][]string{}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		fields := sep.Split(strings.TrimSpace(line), -1)
+		if len(fields) >= 6 && strings.HasPrefix(fields[0], "test.help.") {
+			rows[fields[0]] = fields[1:]
+		}
+	}
+	g.Expect(rows).To(Equal(map[string][]string{
+		"test.help.clientCert": {
+			"string", "-", "******", "env AIRMID_TEST_HELP_CLIENT_CERT", "testHelpBean.cert",
+		},
+		"test.help.hosts": {"[]string", "a,b", "x,y", "flags --test.help.hosts", "testHelpBean.hosts"},
+		"test.help.labels": {
+			"map[string]string", "-", "a=1,b=2", "flags --test.help.labels.a", "testHelpBean.labels",
+		},
+		"test.help.license": {"string", "-", "******", "env AIRMID_TEST_HELP_LICENSE", "testHelpBean.license"},
+		"test.help.name":    {"string", "-", "<unset>", "-", "testHelpBean.name"},
+		"test.help.port":    {"int", "8080", "8080", "default", "testHelpBean.port", "the listen port"},
+		"test.help.secret":  {"string", "******", "******", "default", "testHelpBean.secret"},
+		"test.help.timeout": {"time.Duration", "-", "3s", "env AIRMID_TEST_HELP_TIMEOUT", "testHelpBean.timeout"},
+	}))
+	g.Expect(buf.String()).To(ContainSubstring("airmid.shutdown.duration"))
+}
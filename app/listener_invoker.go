@@ -21,9 +21,23 @@ package app
 
 import (
 	"context"
+	"reflect"
 )
 
 // ListenerInvoker will invoke listener function with given argument.
+//
+// The listener receive the events which are assignable to its event type, so the event hierarchy can be
+// modeled by interfaces, e.g. the listener of 'ConfigEvent' interface receive all of events implementing it,
+// and the listener of ApplicationEvent receive all events.
 type ListenerInvoker interface {
-	Invoke(ctx context.Context, event ApplicationEvent)
+	// Invoke notify the listener of event, it return the error of sync listener. The error of
+	// async listener is logged, because it's handled after the event is published.
+	Invoke(ctx context.Context, event ApplicationEvent) error
+}
+
+// eventTypeMatcher is implemented by the invoker which only handle specific event types, the invokers
+// are matched by the concrete event type once and cached by the publisher.
+type eventTypeMatcher interface {
+	// supportsEventType report whether the event of typ is handled, the typ is nil for nil event.
+	supportsEventType(typ reflect.Type) bool
 }
@@ -37,7 +37,7 @@ type LoggerProvider interface {
 type loggerStartupHandlerConfiguration struct {
 	loggerProviders []LoggerProvider `airmid:"autowire:?"`
 
-	handlerType         string `airmid:"value:${airmid.logger.handler.type:=json}"`
+	handlerType         string `airmid:"value:${airmid.logger.handler.type:=json}" validate:"oneof=json|text"`
 	handlerOptAddSource bool   `airmid:"value:${airmid.logger.handler.opt.source:=true}"`
 	handlerOptLevel     string `airmid:"value:${airmid.logger.handler.opt.level:=INFO}"`
 }
@@ -112,6 +112,7 @@ func TestLoggerStartupHandler(t *testing.T) {
 
 		err = h.AfterLoadProps(context.Background(), app, nil)
 		g.Expect(err).To(HaveOccurred())
-		g.Expect(err.Error()).To(MatchRegexp(`unknown logger handler type iii`))
+		g.Expect(err.Error()).To(MatchRegexp(
+			`Invalid value of key 'airmid.logger.handler.type': 'iii' must be one of 'json', 'text'`))
 	})
 }
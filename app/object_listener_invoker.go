@@ -21,8 +21,8 @@ package app
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/anyvoxel/airmid/anvil"
 	"github.com/anyvoxel/airmid/anvil/xerrors"
 	"github.com/anyvoxel/airmid/ioc"
 )
@@ -34,19 +34,17 @@ type objectListenerInvoker struct {
 	app Application
 }
 
-func (i *objectListenerInvoker) Invoke(ctx context.Context, event ApplicationEvent) {
+func (i *objectListenerInvoker) Invoke(ctx context.Context, event ApplicationEvent) error {
 	if i.obj != nil {
 		i.obj.OnApplicationEvent(ctx, event)
 	}
 
 	if i.objAsync != nil {
-		anvil.Must(i.app.Submit(func() {
-			//nolint:contextcheck
-			anvil.SafeRun(ctx, func(ctx context.Context) {
-				i.objAsync.OnApplicationEventAsync(ctx, event)
-			})
-		}))
+		return submitAsyncEvent(ctx, i.app, i, fmt.Sprintf("%T", i.objAsync), func(ctx context.Context) {
+			i.objAsync.OnApplicationEventAsync(ctx, event)
+		})
 	}
+	return nil
 }
 
 // NewObjectListenerInvoker will return the object ListenInvoker impl.
@@ -19,6 +19,8 @@
 
 package app
 
+import "io"
+
 // Option applies a configuration option value to a application.
 type Option interface {
 	apply(*option)
@@ -27,6 +29,19 @@ type Option interface {
 // option contains configuration options for a application.
 type option struct {
 	attrs []Attribute
+
+	// args is the command line args to parse, nil means os.Args[1:]
+	args        []string
+	shortFlags  map[string]string
+	valueFlags  []string
+	listFlags   []string
+	strictFlags bool
+	knownFlags  []string
+	// listSeparator is the separator to split env & flag values, nil means DefaultListSeparator
+	listSeparator *string
+
+	// helpOutput is the writer to print help, nil means os.Stdout
+	helpOutput io.Writer
 }
 
 // optionFunc applies a set of options to a option.
@@ -50,6 +65,94 @@ func WithAttributes(attrs ...Attribute) Option {
 	})
 }
 
+// WithArgs sets the command line args to parse instead of os.Args[1:], WithArgs() means no args.
+func WithArgs(args ...string) Option {
+	return optionFunc(func(o *option) {
+		// The args is copied to non-nil slice, so the empty args won't fallback to os.Args[1:]
+		o.args = append([]string{}, args...)
+	})
+}
+
+// WithShortFlag maps the short flag (e.g. 'p' for '-p') to the property key.
+func WithShortFlag(short string, key string) Option {
+	return optionFunc(func(o *option) {
+		if o.shortFlags == nil {
+			o.shortFlags = map[string]string{}
+		}
+		o.shortFlags[short] = key
+	})
+}
+
+// WithValueFlags declares the keys which take the next arg as value, e.g. '--port 80', the
+// other flags require '=' for value, e.g. '--port=80', and the bare flag is set to 'true'.
+func WithValueFlags(keys ...string) Option {
+	return optionFunc(func(o *option) {
+		o.valueFlags = append(o.valueFlags, keys...)
+	})
+}
+
+// WithListFlags declares the keys which take the next arg as value and accumulate the repeated
+// flags into slice, e.g. '--tag a --tag=b', the other repeated flags override the previous one.
+func WithListFlags(keys ...string) Option {
+	return optionFunc(func(o *option) {
+		o.listFlags = append(o.listFlags, keys...)
+	})
+}
+
+// WithStrictFlags reports the flag which key is not in knownKeys or sub key of them,
+// the flags for airmid (e.g. '--airmid.dotenv.paths') are always known.
+func WithStrictFlags(knownKeys ...string) Option {
+	return optionFunc(func(o *option) {
+		o.strictFlags = true
+		o.knownFlags = append(o.knownFlags, knownKeys...)
+	})
+}
+
+// WithListSeparator sets the separator to split the env, dotenv & flag values into list instead of
+// DefaultListSeparator, the values are never split if sep is empty, e.g. for DSN or JSON values.
+func WithListSeparator(sep string) Option {
+	return optionFunc(func(o *option) {
+		o.listSeparator = &sep
+	})
+}
+
+// WithHelpOutput sets the writer to print help when '--help' is specified.
+func WithHelpOutput(w io.Writer) Option {
+	return optionFunc(func(o *option) {
+		o.helpOutput = w
+	})
+}
+
+// argsOptions return the options for args properties loader.
+func (o *option) argsOptions() []ArgsOption {
+	opts := []ArgsOption{
+		WithArgsAlias("help", helpPropertyKey),
+		WithArgsValueFlags(o.valueFlags...),
+		WithArgsListFlags(o.listFlags...),
+	}
+	if o.args != nil {
+		opts = append(opts, WithArgsSource(o.args))
+	}
+	for short, key := range o.shortFlags {
+		opts = append(opts, WithArgsShortFlag(short, key))
+	}
+	if o.listSeparator != nil {
+		opts = append(opts, WithArgsListSeparator(*o.listSeparator))
+	}
+	if o.strictFlags {
+		opts = append(opts, WithArgsStrict(append([]string{"airmid"}, o.knownFlags...)...))
+	}
+	return opts
+}
+
+// separator return the separator to split env & flag values into list.
+func (o *option) separator() string {
+	if o.listSeparator == nil {
+		return DefaultListSeparator
+	}
+	return *o.listSeparator
+}
+
 func newOption(options []Option) *option {
 	o := &option{}
 	for _, opt := range options {
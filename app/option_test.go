@@ -61,3 +61,47 @@ func TestWithAttributes(t *testing.T) {
 		},
 	}))
 }
+
+func TestArgsOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	o := newOption([]Option{
+		WithArgs("--port", "80", "-v", "--name=x", "file"),
+		WithShortFlag("p", "port"),
+		WithValueFlags("port"),
+		WithListFlags("tag"),
+		WithStrictFlags("port", "v"),
+	})
+	g.Expect(o.args).To(Equal([]string{"--port", "80", "-v", "--name=x", "file"}))
+	g.Expect(o.shortFlags).To(Equal(map[string]string{"p": "port"}))
+
+	l := newOptionArgsPropertiesLoader(o.argsOptions()...)
+	g.Expect(l.args).To(Equal(o.args))
+	g.Expect(l.parser).To(Equal(argsParser{
+		shortFlags: map[string]string{"p": "port"},
+		aliases:    map[string]string{"help": "airmid.help"},
+		valueFlags: map[string]bool{"port": true},
+		listFlags:  map[string]bool{"tag": true},
+		strict:     true,
+		knownFlags: []string{"airmid", "port", "v"},
+		separator:  DefaultListSeparator,
+	}))
+
+	// The empty args doesn't fallback to os.Args[1:]
+	o = newOption([]Option{WithArgs()})
+	l = newOptionArgsPropertiesLoader(o.argsOptions()...)
+	g.Expect(l.args).ToNot(BeNil())
+	g.Expect(l.args).To(BeEmpty())
+}
+
+func TestListSeparatorOption(t *testing.T) {
+	g := NewWithT(t)
+
+	o := newOption(nil)
+	g.Expect(o.separator()).To(Equal(DefaultListSeparator))
+
+	o = newOption([]Option{WithListSeparator("")})
+	g.Expect(o.separator()).To(Equal(""))
+	l := newOptionArgsPropertiesLoader(o.argsOptions()...)
+	g.Expect(l.parser.separator).To(Equal(""))
+}
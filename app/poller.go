@@ -0,0 +1,107 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/anyvoxel/airmid/anvil"
+)
+
+// poller call the poll function in background until it's stopped, the poll function return the
+// duration to wait before next poll, so it can be scheduled earlier than the interval, e.g. debounce.
+type poller struct {
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// start call poll after interval, the interval is used as the next wait if poll panics or return
+// non-positive duration, so the poller never spins. The interval must be positive.
+func (p *poller) start(ctx context.Context, interval time.Duration, poll func(ctx context.Context) time.Duration) {
+	p.stopCh = make(chan struct{})
+	p.doneCh = make(chan struct{})
+	go func() {
+		defer close(p.doneCh)
+
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case <-timer.C:
+				next := interval
+				anvil.SafeRun(ctx, func(ctx context.Context) {
+					if d := poll(ctx); d > 0 {
+						next = d
+					}
+				})
+				timer.Reset(next)
+			}
+		}
+	}()
+}
+
+// stop the polling and wait until it exited or ctx is done, it do nothing if poller isn't started.
+func (p *poller) stop(ctx context.Context) {
+	if p.stopCh == nil {
+		return
+	}
+
+	close(p.stopCh)
+	select {
+	case <-p.doneCh:
+	case <-ctx.Done():
+	}
+}
+
+// debouncer track the fingerprint of polled content, the changed fingerprint is applied only after
+// it's unchanged for the debounce duration.
+type debouncer struct {
+	debounce time.Duration
+
+	// applied is the fingerprint which is applied or reported
+	applied string
+	// pending is the changed fingerprint which is waiting for debounce
+	pending      string
+	pendingSince time.Time
+}
+
+// observe record the fingerprint polled at now, it return whether the fingerprint should be applied,
+// and the duration to wait before next poll, which is the rest of debounce if the change is pending.
+func (d *debouncer) observe(fingerprint string, now time.Time, interval time.Duration) (bool, time.Duration) {
+	if fingerprint == d.applied {
+		d.pending = ""
+		return false, interval
+	}
+
+	if fingerprint != d.pending {
+		d.pending = fingerprint
+		d.pendingSince = now
+	}
+	if elapsed := now.Sub(d.pendingSince); elapsed < d.debounce {
+		return false, d.debounce - elapsed
+	}
+
+	d.applied = fingerprint
+	d.pending = ""
+	return true, interval
+}
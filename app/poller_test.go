@@ -0,0 +1,137 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDebouncerObserve(t *testing.T) {
+	type observe struct {
+		fingerprint string
+		elapsed     time.Duration
+		apply       bool
+		wait        time.Duration
+	}
+	type testCase struct {
+		desp     string
+		debounce time.Duration
+		observes []observe
+	}
+	testCases := []testCase{
+		{
+			desp:     "no debounce",
+			debounce: 0,
+			observes: []observe{
+				{fingerprint: "a", elapsed: 0, apply: false, wait: time.Minute},
+				{fingerprint: "b", elapsed: 0, apply: true, wait: time.Minute},
+				{fingerprint: "b", elapsed: time.Second, apply: false, wait: time.Minute},
+			},
+		},
+		{
+			desp:     "stable after debounce",
+			debounce: 3 * time.Second,
+			observes: []observe{
+				{fingerprint: "b", elapsed: 0, apply: false, wait: 3 * time.Second},
+				{fingerprint: "b", elapsed: time.Second, apply: false, wait: 2 * time.Second},
+				{fingerprint: "b", elapsed: 3 * time.Second, apply: true, wait: time.Minute},
+			},
+		},
+		{
+			desp:     "restart debounce on change",
+			debounce: 3 * time.Second,
+			observes: []observe{
+				{fingerprint: "b", elapsed: 0, apply: false, wait: 3 * time.Second},
+				{fingerprint: "c", elapsed: 2 * time.Second, apply: false, wait: 3 * time.Second},
+				{fingerprint: "c", elapsed: 4 * time.Second, apply: false, wait: time.Second},
+				{fingerprint: "c", elapsed: 5 * time.Second, apply: true, wait: time.Minute},
+			},
+		},
+		{
+			desp:     "revert before debounce",
+			debounce: 3 * time.Second,
+			observes: []observe{
+				{fingerprint: "b", elapsed: 0, apply: false, wait: 3 * time.Second},
+				{fingerprint: "a", elapsed: time.Second, apply: false, wait: time.Minute},
+				{fingerprint: "b", elapsed: 2 * time.Second, apply: false, wait: 3 * time.Second},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			d := &debouncer{debounce: tc.debounce, applied: "a"}
+			now := time.Now()
+			for _, o := range tc.observes {
+				apply, wait := d.observe(o.fingerprint, now.Add(o.elapsed), time.Minute)
+				g.Expect(apply).To(Equal(o.apply), "%+v", o)
+				g.Expect(wait).To(Equal(o.wait), "%+v", o)
+			}
+		})
+	}
+}
+
+func TestPoller(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	// The stopped poller which isn't started do nothing
+	p := &poller{}
+	p.stop(ctx)
+
+	count := int32(0)
+	p.start(ctx, time.Hour, func(context.Context) time.Duration {
+		if atomic.AddInt32(&count, 1) == 1 {
+			panic("poll failed")
+		}
+		return time.Millisecond
+	})
+	g.Consistently(func() int32 { return atomic.LoadInt32(&count) }, 50*time.Millisecond).Should(BeZero())
+	p.stop(ctx)
+
+	p = &poller{}
+	p.start(ctx, time.Millisecond, func(context.Context) time.Duration {
+		if atomic.AddInt32(&count, 1) == 1 {
+			panic("poll failed")
+		}
+		return time.Millisecond
+	})
+	// The panic poll is scheduled with interval
+	g.Eventually(func() int32 { return atomic.LoadInt32(&count) }).Should(BeNumerically(">", 3))
+	p.stop(ctx)
+	stopped := atomic.LoadInt32(&count)
+	g.Consistently(func() int32 { return atomic.LoadInt32(&count) }, 20*time.Millisecond).Should(Equal(stopped))
+
+	// The non-positive wait is replaced by interval
+	count = 0
+	p = &poller{}
+	p.start(ctx, 20*time.Millisecond, func(context.Context) time.Duration {
+		atomic.AddInt32(&count, 1)
+		return 0
+	})
+	g.Eventually(func() int32 { return atomic.LoadInt32(&count) }).Should(BeNumerically(">=", 1))
+	g.Consistently(func() int32 { return atomic.LoadInt32(&count) }, 30*time.Millisecond).Should(BeNumerically("<", 5))
+	p.stop(ctx)
+}
@@ -21,9 +21,18 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
 	"github.com/anyvoxel/airmid/app/env"
 	"github.com/anyvoxel/airmid/ioc/props"
 )
@@ -40,97 +49,468 @@ type PropertiesLoaders interface {
 	Add(p PropertiesLoader) PropertiesLoaders
 }
 
+// DefaultListSeparator is the default separator to split the env & flag value into list.
+const DefaultListSeparator = ","
+
 // PropertiesLoader load convert/load env to properties.
 type envPropertiesLoader struct {
-	envLoader    env.Loader
-	keyConvertFn func(envKey string) string
+	prefix        string
+	envLoader     env.Loader
+	keyConvertFn  func(envKey string) string
+	listSeparator string
+}
+
+// EnvOption configure the env properties loader.
+type EnvOption func(l *envPropertiesLoader)
+
+// WithEnvListSeparator is an option to set the separator to split the env value into list,
+// default is DefaultListSeparator, and the value is never split if sep is empty.
+func WithEnvListSeparator(sep string) EnvOption {
+	return func(l *envPropertiesLoader) {
+		l.listSeparator = sep
+	}
 }
 
 // NewEnvPropertiesLoader return a instance of envPropertiesLoader which implements Properties.
-func NewEnvPropertiesLoader(prefix string, keyConvertFn func(string) string,
+func NewEnvPropertiesLoader(prefix string, keyConvertFn func(string) string, opts ...EnvOption,
 ) PropertiesLoader {
 	envIncludePattern := os.Getenv("AIRMID_INCLUDE_ENV_PATTERNS")
 	envExcludePattern := os.Getenv("AIRMID_EXCLUDE_ENV_PATTERNS")
 
-	return &envPropertiesLoader{
-		keyConvertFn: keyConvertFn,
+	l := &envPropertiesLoader{
+		prefix:        prefix,
+		keyConvertFn:  keyConvertFn,
+		listSeparator: DefaultListSeparator,
 		envLoader: env.NewEnvLoader(
 			env.WithPrefixOption(prefix),
 			env.WithEnvIncludePattern(envIncludePattern),
 			env.WithEnvExcludePattern(envExcludePattern),
 		),
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
-// LoadProperties loads properties from environment variables.
+// LoadProperties loads properties from environment variables, the env name
+// will be recorded as the location of property.
 func (l *envPropertiesLoader) LoadProperties(ctx context.Context, p props.Properties) error {
-	for k, v := range l.envLoader.Load(ctx) {
-		key := l.keyConvertFn(k)
-		// if the env value is array, set to properties as key[0]=value0, key[1]=value1.
-		if values := strings.Split(v, ","); len(values) > 1 {
-			if err := p.Set(ctx, key, values); err != nil {
-				return err
+	return setEnvProperties(ctx, p, l.envLoader.Load(ctx), l.keyConvertFn, l.listSeparator, func(k string) string {
+		return l.prefix + k
+	})
+}
+
+func setEnvProperties(
+	ctx context.Context,
+	p props.Properties,
+	envs map[string]string,
+	keyConvertFn func(envKey string) string,
+	sep string,
+	locate func(envKey string) string,
+) error {
+	values := make(map[string]any, len(envs))
+	locations := make(map[string]string, len(envs))
+	for k, v := range envs {
+		key := keyConvertFn(k)
+		values[key] = listValue(splitListValue(v, sep))
+		locations[key] = locate(k)
+	}
+	return p.SetAll(props.ContextWithLocation(ctx, keyLocator(locations)), values)
+}
+
+// keyLocator return the LocationFunc which locate the flattened key by its nearest parent key
+// in locations, e.g. 'servers[0]' and 'db.host' are located by 'servers' and 'db'.
+func keyLocator(locations map[string]string) props.LocationFunc {
+	return func(key string) string {
+		for {
+			if loc, ok := locations[key]; ok {
+				return loc
+			}
+			i := strings.LastIndexAny(key, ".[")
+			if i <= 0 {
+				return ""
+			}
+			key = key[:i]
+		}
+	}
+}
+
+// splitListValue split the env or flag value into list by sep, the escaped separator (e.g. '\,')
+// is unescaped and kept in the element. The value is never split if sep is empty or the value
+// is JSON array or object, which is expanded into nested keys by listValue.
+func splitListValue(v string, sep string) []string {
+	if sep == "" || isJSONValue(v) {
+		return []string{v}
+	}
+
+	values := []string{}
+	var b strings.Builder
+	for i := 0; i < len(v); {
+		switch {
+		case v[i] == '\\' && strings.HasPrefix(v[i+1:], sep):
+			b.WriteString(sep)
+			i += 1 + len(sep)
+		case strings.HasPrefix(v[i:], sep):
+			values = append(values, b.String())
+			b.Reset()
+			i += len(sep)
+		default:
+			b.WriteByte(v[i])
+			i++
+		}
+	}
+	return append(values, b.String())
+}
+
+// listValue return the value to set into properties for the split values:
+//  1. the single value is set as key=value
+//  2. the multiple values are set as key[0]=value0, key[1]=value1
+//  3. the JSON array or object (e.g. '[{"host":"a"}]') is expanded into nested keys, e.g. key[0].host=a
+func listValue(values []string) any {
+	if len(values) == 1 {
+		return jsonOrString(values[0])
+	}
+
+	ret := make([]any, 0, len(values))
+	for _, v := range values {
+		ret = append(ret, jsonOrString(v))
+	}
+	return ret
+}
+
+// isJSONValue return true if v is a valid JSON array or object.
+func isJSONValue(v string) bool {
+	v = strings.TrimSpace(v)
+	if v == "" || (v[0] != '[' && v[0] != '{') {
+		return false
+	}
+	return json.Valid([]byte(v))
+}
+
+// jsonOrString return the decoded value if v is JSON array or object, the numbers are kept as
+// they are written and the null is decoded as empty string.
+func jsonOrString(v string) any {
+	if !isJSONValue(v) {
+		return v
+	}
+
+	var ret any
+	dec := json.NewDecoder(strings.NewReader(v))
+	dec.UseNumber()
+	if err := dec.Decode(&ret); err != nil {
+		return v
+	}
+	return replaceJSONNull(ret)
+}
+
+func replaceJSONNull(v any) any {
+	switch vv := v.(type) {
+	case nil:
+		return ""
+	case []any:
+		for i := range vv {
+			vv[i] = replaceJSONNull(vv[i])
+		}
+	case map[string]any:
+		for k := range vv {
+			vv[k] = replaceJSONNull(vv[k])
+		}
+	}
+	return v
+}
+
+// dotenvPropertiesLoader load the variables in dotenv files to properties, the variables
+// are filtered & converted as same as envPropertiesLoader.
+type dotenvPropertiesLoader struct {
+	prefix        string
+	paths         []string
+	keyConvertFn  func(envKey string) string
+	listSeparator string
+}
+
+// NewDotenvPropertiesLoader return a instance of dotenvPropertiesLoader which read the dotenv
+// files in paths, the variable in later file will overwrite the former one, and the missing
+// file will be ignored.
+func NewDotenvPropertiesLoader(prefix string, keyConvertFn func(string) string, paths ...string) PropertiesLoader {
+	return newDotenvPropertiesLoader(prefix, keyConvertFn, DefaultListSeparator, paths...)
+}
+
+func newDotenvPropertiesLoader(
+	prefix string,
+	keyConvertFn func(string) string,
+	sep string,
+	paths ...string,
+) *dotenvPropertiesLoader {
+	return &dotenvPropertiesLoader{
+		prefix:        prefix,
+		paths:         paths,
+		keyConvertFn:  keyConvertFn,
+		listSeparator: sep,
+	}
+}
+
+// LoadProperties loads properties from dotenv files, the file path and line number will be
+// recorded as the location of property, e.g. '.env line 3'.
+func (l *dotenvPropertiesLoader) LoadProperties(ctx context.Context, p props.Properties) error {
+	values := map[string]string{}
+	locations := map[string]string{}
+	// The real environment variables take precedence over the dotenv variables in expansion,
+	// as same as they are in properties.
+	lookup := func(key string) (string, bool) {
+		if v, ok := os.LookupEnv(key); ok {
+			return v, true
+		}
+		v, ok := values[key]
+		return v, ok
+	}
+
+	for _, path := range l.paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				slogctx.FromCtx(ctx).DebugContext(ctx, "dotenv file not found, skip it", slog.String("Path", path))
+				continue
+			}
+			return xerrors.Wrapf(err, "Cannot read dotenv file '%v'", path)
+		}
+
+		entries, err := env.ParseDotenv(data, lookup)
+		if err != nil {
+			return xerrors.Wrapf(err, "Cannot parse dotenv file '%v'", path)
+		}
+		for _, e := range entries {
+			values[e.Key] = e.Value
+			locations[e.Key] = path + " line " + strconv.Itoa(e.Line)
+		}
+	}
+
+	envLoader := env.NewEnvLoader(
+		env.WithPrefixOption(l.prefix),
+		env.WithEnvIncludePattern(os.Getenv("AIRMID_INCLUDE_ENV_PATTERNS")),
+		env.WithEnvExcludePattern(os.Getenv("AIRMID_EXCLUDE_ENV_PATTERNS")),
+		env.WithEnviron(func() []string {
+			environ := make([]string, 0, len(values))
+			for k, v := range values {
+				environ = append(environ, k+"="+v)
+			}
+			return environ
+		}),
+	)
+	return setEnvProperties(ctx, p, envLoader.Load(ctx), l.keyConvertFn, l.listSeparator, func(k string) string {
+		return locations[l.prefix+k]
+	})
+}
+
+// secretDirPropertiesLoader load the files in secret directories to properties, e.g. the secrets
+// mounted by kubernetes or docker.
+type secretDirPropertiesLoader struct {
+	dirs []string
+}
+
+// NewSecretDirPropertiesLoader return a instance of secretDirPropertiesLoader which read the files
+// in dirs, the relative path of file is the key (e.g. 'db/password' -> 'db.password'), and the
+// content without trailing newlines is the value. The file in later dir will overwrite the former
+// one, the hidden files (e.g. '..data' of kubernetes) and missing dirs will be ignored. All of the
+// keys are marked as secret, so the values will be redacted in logs.
+func NewSecretDirPropertiesLoader(dirs ...string) PropertiesLoader {
+	return &secretDirPropertiesLoader{
+		dirs: dirs,
+	}
+}
+
+// LoadProperties loads properties from secret files, the file path will be recorded as the
+// location of property.
+func (l *secretDirPropertiesLoader) LoadProperties(ctx context.Context, p props.Properties) error {
+	for _, dir := range l.dirs {
+		if _, err := os.Stat(dir); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				slogctx.FromCtx(ctx).DebugContext(ctx, "secret dir not found, skip it", slog.String("Path", dir))
+				continue
 			}
-		} else {
-			// if the env value is a string or an array which only has one element, set to properties as key=value.
-			if err := p.Set(ctx, key, v); err != nil {
+			return xerrors.Wrapf(err, "Cannot read secret dir '%v'", dir)
+		}
+
+		// The files of dir are set in one batch
+		values := map[string]any{}
+		locations := map[string]string{}
+		if err := l.loadDir(dir, "", values, locations); err != nil {
+			return err
+		}
+		if err := p.SetAll(props.ContextWithLocation(ctx, keyLocator(locations)), values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDir read the files in dir recursively, and record the value & location of each key.
+func (l *secretDirPropertiesLoader) loadDir(
+	dir string, prefix string, values map[string]any, locations map[string]string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return xerrors.Wrapf(err, "Cannot read secret dir '%v'", dir)
+	}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		key := entry.Name()
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		// The entries of kubernetes secret are symlinks, so we must follow them
+		info, err := os.Stat(path)
+		if err != nil {
+			return xerrors.Wrapf(err, "Cannot read secret file '%v'", path)
+		}
+		if info.IsDir() {
+			if err := l.loadDir(path, key, values, locations); err != nil {
 				return err
 			}
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return xerrors.Wrapf(err, "Cannot read secret file '%v'", path)
 		}
+
+		props.MarkSecretKeys(key)
+		values[key] = strings.TrimRight(string(data), "\r\n")
+		locations[key] = path
 	}
 	return nil
 }
 
 // DefaultEnvKeyConvertFunc convert the env key to prop key by following rules:
 // 1. to lowercase
-// 2. replace '_' to '.'.
+// 2. replace '__' to literal '_', e.g. the map key 'labels.app_name' is 'LABELS_APP__NAME'
+// 3. replace '_' to '.'.
+//
+// The converted key is matched in relaxed binding, e.g. 'HTTP_READ_TIMEOUT' is matched with
+// 'http.read-timeout' and 'http.readTimeout'.
 var DefaultEnvKeyConvertFunc = func(envKey string) string {
-	return strings.ReplaceAll(strings.ToLower(envKey), "_", ".")
+	words := strings.Split(strings.ToLower(envKey), "__")
+	for i := range words {
+		words[i] = strings.ReplaceAll(words[i], "_", ".")
+	}
+	return strings.Join(words, "_")
 }
 
 // OptionArgsPropertiesLoader is used for loading args properties.
-type optionArgsPropertiesLoader struct{}
+type optionArgsPropertiesLoader struct {
+	args   []string
+	parser argsParser
+
+	// positional is the positional args after LoadProperties
+	positional []string
+}
+
+// ArgsOption configure the args properties loader.
+type ArgsOption func(l *optionArgsPropertiesLoader)
+
+// WithArgsSource is an option to set the args to parse, default is os.Args[1:].
+func WithArgsSource(args []string) ArgsOption {
+	return func(l *optionArgsPropertiesLoader) {
+		l.args = args
+	}
+}
+
+// WithArgsShortFlag is an option to map the short flag (e.g. 'p' for '-p') to key.
+func WithArgsShortFlag(short string, key string) ArgsOption {
+	return func(l *optionArgsPropertiesLoader) {
+		l.parser.shortFlags[short] = key
+	}
+}
+
+// WithArgsAlias is an option to map the long flag (e.g. 'help' for '--help') to key.
+func WithArgsAlias(name string, key string) ArgsOption {
+	return func(l *optionArgsPropertiesLoader) {
+		l.parser.aliases[name] = key
+	}
+}
+
+// WithArgsValueFlags is an option to declare the keys which take the next arg as value, e.g. '--port 80'.
+func WithArgsValueFlags(keys ...string) ArgsOption {
+	return func(l *optionArgsPropertiesLoader) {
+		for _, k := range keys {
+			l.parser.valueFlags[k] = true
+		}
+	}
+}
+
+// WithArgsListFlags is an option to declare the keys which take the next arg as value and
+// accumulate the repeated flags into slice, e.g. '--tag a --tag b'.
+func WithArgsListFlags(keys ...string) ArgsOption {
+	return func(l *optionArgsPropertiesLoader) {
+		for _, k := range keys {
+			l.parser.listFlags[k] = true
+		}
+	}
+}
+
+// WithArgsStrict is an option to report the flag which key is not in knownKeys or sub key of them.
+func WithArgsStrict(knownKeys ...string) ArgsOption {
+	return func(l *optionArgsPropertiesLoader) {
+		l.parser.strict = true
+		l.parser.knownFlags = append(l.parser.knownFlags, knownKeys...)
+	}
+}
+
+// WithArgsListSeparator is an option to set the separator to split the flag value into list,
+// default is DefaultListSeparator, and the value is never split if sep is empty.
+func WithArgsListSeparator(sep string) ArgsOption {
+	return func(l *optionArgsPropertiesLoader) {
+		l.parser.separator = sep
+	}
+}
 
 // NewOptionArgsPropertiesLoader returns a new instance of optionArgsPropertiesLoader.
-func NewOptionArgsPropertiesLoader() PropertiesLoader {
-	return &optionArgsPropertiesLoader{}
+func NewOptionArgsPropertiesLoader(opts ...ArgsOption) PropertiesLoader {
+	return newOptionArgsPropertiesLoader(opts...)
 }
 
-// LoadProperties loads properties from command line args.
-//
-//nolint:revive
+func newOptionArgsPropertiesLoader(opts ...ArgsOption) *optionArgsPropertiesLoader {
+	l := &optionArgsPropertiesLoader{
+		args: os.Args[1:],
+		parser: argsParser{
+			shortFlags: map[string]string{},
+			aliases:    map[string]string{},
+			valueFlags: map[string]bool{},
+			listFlags:  map[string]bool{},
+			separator:  DefaultListSeparator,
+		},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// LoadProperties loads properties from command line args, the flag name will be
+// recorded as the location of property.
 func (o *optionArgsPropertiesLoader) LoadProperties(ctx context.Context, p props.Properties) error {
-	for _, arg := range os.Args[1:] {
-		// TODO: support non-optional args, such as "--airmid.application.port 8080 -flag false"
-		parts := strings.SplitN(arg, "=", 2)
-		switch len(parts) {
-		case 1:
-			key := strings.TrimLeft(parts[0], "-")
-			if len(key) == 0 {
-				continue
-			}
-			if err := p.Set(ctx, key, true); err != nil {
-				return err
-			}
-		case 2:
-			key := strings.TrimLeft(parts[0], "-")
-			if len(key) == 0 {
-				continue
-			}
-			rawValue := parts[1]
-			// if the flag value is array, set to properties as key[0]=value0, key[1]=value1.
-			if values := strings.Split(rawValue, ","); len(values) > 1 {
-				if err := p.Set(ctx, key, values); err != nil {
-					return err
-				}
-			} else {
-				// if the flag value is a string or an array which only has one element, set to properties as key=value.
-				if err := p.Set(ctx, key, rawValue); err != nil {
-					return err
-				}
-			}
-		}
+	flags, positional, err := o.parser.parse(o.args)
+	if err != nil {
+		return err
+	}
+
+	// The flags are set in one batch, and the latter one win if the flags have same key
+	values := make(map[string]any, len(flags))
+	locations := make(map[string]string, len(flags))
+	for _, f := range flags {
+		values[f.key] = listValue(f.values)
+		locations[f.key] = f.name
+	}
+	if err := p.SetAll(props.ContextWithLocation(ctx, keyLocator(locations)), values); err != nil {
+		return err
 	}
+	o.positional = positional
 	return nil
 }
 
@@ -22,6 +22,7 @@ package app
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/onsi/gomega"
@@ -133,6 +134,111 @@ func TestEnvPropertiesLoader_LoadProperties(t *testing.T) {
 	}
 }
 
+func TestSplitListValue(t *testing.T) {
+	type testCase struct {
+		desp   string
+		value  string
+		sep    string
+		expect []string
+	}
+	testCases := []testCase{
+		{desp: "single value", value: "a", sep: ",", expect: []string{"a"}},
+		{desp: "empty value", value: "", sep: ",", expect: []string{""}},
+		{desp: "split value", value: "a,,b", sep: ",", expect: []string{"a", "", "b"}},
+		{desp: "escaped separator", value: `a\,b,c\d`, sep: ",", expect: []string{"a,b", `c\d`}},
+		{desp: "multiple chars separator", value: "a,b||c", sep: "||", expect: []string{"a,b", "c"}},
+		{desp: "no separator", value: "a,b", sep: "", expect: []string{"a,b"}},
+		{desp: "JSON array", value: ` [1, 2]`, sep: ",", expect: []string{` [1, 2]`}},
+		{desp: "invalid JSON", value: `[1,2`, sep: ",", expect: []string{"[1", "2"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			g.Expect(splitListValue(tc.value, tc.sep)).To(gomega.Equal(tc.expect))
+		})
+	}
+}
+
+func TestEnvPropertiesLoaderListValue(t *testing.T) {
+	t.Setenv("AIRMID_INCLUDE_ENV_PATTERNS", "")
+	t.Setenv("AIRMID_EXCLUDE_ENV_PATTERNS", "")
+	t.Setenv("AIRMID_TEST_DSN", `user:p\,w@tcp(h)/db`)
+	t.Setenv("AIRMID_TEST_SERVERS", `[{"host":"a","port":80,"tags":["x","y"]},{"host":"b","opt":null}]`)
+	t.Setenv("AIRMID_TEST_DB", `{"host":"h","pool":{"max":10}}`)
+	t.Setenv("AIRMID_TEST_HOSTS", "a;b,c")
+	t.Setenv("AIRMID_TEST_PARENT", "x")
+	t.Setenv("AIRMID_TEST_PARENT_HOST", "h")
+
+	ctx := context.Background()
+	g := gomega.NewWithT(t)
+	p := props.NewProperties()
+	source := props.NewPropertySource("env")
+	err := NewEnvPropertiesLoader("AIRMID_", DefaultEnvKeyConvertFunc).LoadProperties(ctx, source)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	p.AddPropertySource(source, props.PrecedenceEnv)
+	// The nested keys are located by the env of parent key
+	g.Expect(source.Location("test.servers[0].tags[1]")).Should(gomega.Equal("AIRMID_TEST_SERVERS"))
+	g.Expect(source.Location("test.parent.host")).Should(gomega.Equal("AIRMID_TEST_PARENT_HOST"))
+	for k, v := range map[string]string{
+		"test.parent":             "x",
+		"test.parent.host":        "h",
+		"test.dsn":                "user:p,w@tcp(h)/db",
+		"test.servers[0].host":    "a",
+		"test.servers[0].port":    "80",
+		"test.servers[0].tags[1]": "y",
+		"test.servers[1].host":    "b",
+		"test.servers[1].opt":     "",
+		"test.db.host":            "h",
+		"test.db.pool.max":        "10",
+		"test.hosts[0]":           "a;b",
+		"test.hosts[1]":           "c",
+	} {
+		g.Expect(p.Get(ctx, k)).Should(gomega.Equal(v), k)
+	}
+
+	p = props.NewProperties()
+	err = NewEnvPropertiesLoader("AIRMID_", DefaultEnvKeyConvertFunc, WithEnvListSeparator(";")).LoadProperties(ctx, p)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	var hosts []string
+	_, err = p.Get(ctx, "test.hosts", props.WithTarget(&hosts))
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(hosts).Should(gomega.Equal([]string{"a", "b,c"}))
+
+	// The repeated JSON list flags are accumulated into slice
+	p = props.NewProperties()
+	err = NewOptionArgsPropertiesLoader(WithArgsSource([]string{
+		`--test.servers={"host":"a"}`, `--test.servers={"host":"b"}`, "--test.hosts=a,b", `--test.dsn=a\,b`,
+	}), WithArgsListFlags("test.servers"), WithArgsListSeparator("")).LoadProperties(ctx, p)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	for k, v := range map[string]string{
+		"test.servers[0].host": "a",
+		"test.servers[1].host": "b",
+		"test.hosts":           "a,b",
+		"test.dsn":             `a\,b`,
+	} {
+		g.Expect(p.Get(ctx, k)).Should(gomega.Equal(v), k)
+	}
+}
+
+func TestDefaultEnvKeyConvertFunc(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	g.Expect(DefaultEnvKeyConvertFunc("GPOOL_MAX_BLOCKING_TASKS")).To(gomega.Equal("gpool.max.blocking.tasks"))
+	g.Expect(DefaultEnvKeyConvertFunc("LABELS_APP__NAME")).To(gomega.Equal("labels.app_name"))
+	g.Expect(DefaultEnvKeyConvertFunc("A___B")).To(gomega.Equal("a_.b"))
+
+	// The converted key is matched in relaxed binding
+	t.Setenv("AIRMID_INCLUDE_ENV_PATTERNS", "")
+	t.Setenv("AIRMID_EXCLUDE_ENV_PATTERNS", "")
+	t.Setenv("AIRMID_TEST_HTTP_READ_TIMEOUT", "3s")
+	ctx := context.Background()
+	p := props.NewProperties()
+	g.Expect(NewEnvPropertiesLoader("AIRMID_", DefaultEnvKeyConvertFunc).LoadProperties(ctx, p)).To(gomega.Succeed())
+	g.Expect(p.Get(ctx, "test.http.read-timeout")).To(gomega.Equal("3s"))
+	g.Expect(p.Get(ctx, "test.http.readTimeout")).To(gomega.Equal("3s"))
+}
+
 func TestOptionArgsPropertiesLoader_LoadProperties(t *testing.T) {
 	type testCase struct {
 		desc       string
@@ -199,6 +305,35 @@ func TestOptionArgsPropertiesLoader_LoadProperties(t *testing.T) {
 				"test.flag[2]": "test2",
 			},
 		},
+		{
+			desc: "Read scalar parent after child",
+			testBefore: func(_ props.Properties) {
+				os.Args = []string{"", "--db.host=h", "--db=x"}
+			},
+			expect: map[string]string{
+				"db.host": "h",
+				"db":      "x",
+			},
+		},
+		{
+			desc: "Read scalar parent before child",
+			testBefore: func(_ props.Properties) {
+				os.Args = []string{"", "--db=x", "--db.host=h"}
+			},
+			expect: map[string]string{
+				"db.host": "h",
+				"db":      "x",
+			},
+		},
+		{
+			desc: "Read repeated flag",
+			testBefore: func(_ props.Properties) {
+				os.Args = []string{"", "--test=a", "--test=b"}
+			},
+			expect: map[string]string{
+				"test": "b",
+			},
+		},
 	}
 
 	for _, c := range testCases {
@@ -247,3 +382,209 @@ func TestPropertiesLoadersImpl_LoadProperties(t *testing.T) {
 	v, _ := p.Get(context.Background(), "test1")
 	g.Expect(v).Should(gomega.Equal("test1"))
 }
+
+func TestPropertiesLoaderLocation(t *testing.T) {
+	g := gomega.NewWithT(t)
+	t.Setenv("AIRMID_TEST_PORT", "8080")
+	args := os.Args
+	defer func() {
+		os.Args = args
+	}()
+	os.Args = []string{"", "--test.host=localhost"}
+
+	p := props.NewProperties()
+	envSource := props.NewPropertySource("env")
+	err := NewEnvPropertiesLoader("AIRMID_", DefaultEnvKeyConvertFunc).LoadProperties(context.Background(), envSource)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	p.AddPropertySource(envSource, props.PrecedenceEnv)
+
+	flagsSource := props.NewPropertySource("flags")
+	err = NewOptionArgsPropertiesLoader().LoadProperties(context.Background(), flagsSource)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	p.AddPropertySource(flagsSource, props.PrecedenceFlags)
+
+	origin, err := p.Origin(context.Background(), "test.port")
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(origin.String()).Should(gomega.Equal("env AIRMID_TEST_PORT"))
+
+	origin, err = p.Origin(context.Background(), "test.host")
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(origin.String()).Should(gomega.Equal("flags --test.host"))
+}
+
+func TestDotenvPropertiesLoader(t *testing.T) {
+	t.Setenv("AIRMID_INCLUDE_ENV_PATTERNS", "")
+	t.Setenv("AIRMID_EXCLUDE_ENV_PATTERNS", "")
+	dir := t.TempDir()
+	writeFile := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		g := gomega.NewWithT(t)
+		g.Expect(os.WriteFile(path, []byte(content), 0o600)).To(gomega.Succeed())
+		return path
+	}
+	base := writeFile("base.env", `# base
+export AIRMID_DB_HOST=localhost
+AIRMID_DB_PORT=3306
+AIRMID_DB_URL="${AIRMID_DB_HOST}:${AIRMID_DB_PORT}"
+AIRMID_SERVERS=s1,s2
+OTHER_KEY=other
+AIRMID_SECRET='p@ss'
+`)
+	local := writeFile("local.env", "AIRMID_DB_PORT=3307\n")
+	malformed := writeFile("malformed.env", "AIRMID_A=1\nAIRMID_B\n")
+
+	t.Run("load dotenv files", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		t.Setenv("AIRMID_DB_HOST", "real-host")
+
+		p := props.NewProperties()
+		dotenvSource := props.NewPropertySource("dotenv")
+		err := NewDotenvPropertiesLoader(
+			"AIRMID_", DefaultEnvKeyConvertFunc, base, filepath.Join(dir, "missing.env"), local,
+		).LoadProperties(context.Background(), dotenvSource)
+		g.Expect(err).ShouldNot(gomega.HaveOccurred())
+		p.AddPropertySource(dotenvSource, props.PrecedenceDotenv)
+
+		envSource := props.NewPropertySource("env")
+		err = NewEnvPropertiesLoader("AIRMID_", DefaultEnvKeyConvertFunc).LoadProperties(context.Background(), envSource)
+		g.Expect(err).ShouldNot(gomega.HaveOccurred())
+		p.AddPropertySource(envSource, props.PrecedenceEnv)
+
+		for k, expect := range map[string]string{
+			"db.host":    "real-host",
+			"db.port":    "3307",
+			"db.url":     "real-host:3306",
+			"servers[1]": "s2",
+			"secret":     "p@ss",
+		} {
+			v, err := p.Get(context.Background(), k)
+			g.Expect(err).ShouldNot(gomega.HaveOccurred())
+			g.Expect(v).Should(gomega.Equal(expect), k)
+		}
+		_, err = p.Get(context.Background(), "other.key")
+		g.Expect(err).Should(gomega.HaveOccurred())
+
+		origin, err := p.Origin(context.Background(), "db.host")
+		g.Expect(err).ShouldNot(gomega.HaveOccurred())
+		g.Expect(origin.String()).Should(gomega.Equal("env AIRMID_DB_HOST"))
+		origin, err = p.Origin(context.Background(), "db.port")
+		g.Expect(err).ShouldNot(gomega.HaveOccurred())
+		g.Expect(origin.String()).Should(gomega.Equal("dotenv " + local + " line 1"))
+		origin, err = p.Origin(context.Background(), "servers")
+		g.Expect(err).ShouldNot(gomega.HaveOccurred())
+		g.Expect(origin.String()).Should(gomega.Equal("dotenv " + base + " line 5"))
+	})
+
+	t.Run("malformed dotenv file", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		err := NewDotenvPropertiesLoader("AIRMID_", DefaultEnvKeyConvertFunc, malformed).LoadProperties(
+			context.Background(), props.NewPropertySource("dotenv"))
+		g.Expect(err).Should(gomega.HaveOccurred())
+		g.Expect(err.Error()).Should(gomega.Equal(
+			"Cannot parse dotenv file '" + malformed + "': Invalid line 2, it must be 'KEY=value'"))
+	})
+
+	t.Run("load dotenv paths from flags", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		args := os.Args
+		defer func() {
+			os.Args = args
+		}()
+		os.Args = []string{"", "--airmid.dotenv.paths=" + base + "," + local}
+
+		p := props.NewProperties()
+		err := (&airmidApplication{}).loadPropsFromEnvAndFlags(context.Background(), p, newOption(nil))
+		g.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+		v, err := p.Get(context.Background(), "db.port")
+		g.Expect(err).ShouldNot(gomega.HaveOccurred())
+		g.Expect(v).Should(gomega.Equal("3307"))
+	})
+}
+
+func TestOptionArgsPropertiesLoaderWithOptions(t *testing.T) {
+	t.Run("load args", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		t.Setenv("AIRMID_INCLUDE_ENV_PATTERNS", "")
+		t.Setenv("AIRMID_EXCLUDE_ENV_PATTERNS", "")
+
+		a := &airmidApplication{}
+		p := props.NewProperties()
+		err := a.loadPropsFromEnvAndFlags(context.Background(), p, newOption([]Option{
+			WithArgs("serve", "-p", "8080", "--tag", "a", "--tag", "b", "--verbose", "config.yml", "--", "-x"),
+			WithShortFlag("p", "server.port"),
+			WithValueFlags("server.port"),
+			WithListFlags("tag"),
+		}))
+		g.Expect(err).ShouldNot(gomega.HaveOccurred())
+		g.Expect(a.Args()).Should(gomega.Equal([]string{"serve", "config.yml", "-x"}))
+
+		for k, expect := range map[string]string{
+			"server.port": "8080",
+			"tag[0]":      "a",
+			"tag[1]":      "b",
+			"verbose":     "true",
+		} {
+			v, err := p.Get(context.Background(), k)
+			g.Expect(err).ShouldNot(gomega.HaveOccurred())
+			g.Expect(v).Should(gomega.Equal(expect), k)
+		}
+
+		origin, err := p.Origin(context.Background(), "server.port")
+		g.Expect(err).ShouldNot(gomega.HaveOccurred())
+		g.Expect(origin.String()).Should(gomega.Equal("flags -p"))
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		err := (&airmidApplication{}).loadPropsFromEnvAndFlags(context.Background(), props.NewProperties(), newOption([]Option{
+			WithArgs("--airmid.dotenv.paths=x.env", "--port=80", "--unknown"),
+			WithStrictFlags("port"),
+		}))
+		g.Expect(err).Should(gomega.MatchError("Unknown flag '--unknown'"))
+	})
+}
+
+func TestSecretDirPropertiesLoader(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+
+	// The kubernetes secret volume links the keys to the hidden timestamped dir
+	k8sDir := t.TempDir()
+	g.Expect(os.MkdirAll(filepath.Join(k8sDir, "..2025_01_01", "tls"), 0o700)).To(gomega.Succeed())
+	g.Expect(os.WriteFile(filepath.Join(k8sDir, "..2025_01_01", "password"), []byte("p1\n"), 0o600)).
+		To(gomega.Succeed())
+	g.Expect(os.WriteFile(filepath.Join(k8sDir, "..2025_01_01", "tls", "cert"), []byte("c1\r\n"), 0o600)).
+		To(gomega.Succeed())
+	g.Expect(os.Symlink("..2025_01_01", filepath.Join(k8sDir, "..data"))).To(gomega.Succeed())
+	g.Expect(os.Symlink(filepath.Join("..data", "password"), filepath.Join(k8sDir, "password"))).To(gomega.Succeed())
+	g.Expect(os.Symlink(filepath.Join("..data", "tls"), filepath.Join(k8sDir, "tls"))).To(gomega.Succeed())
+
+	dockerDir := t.TempDir()
+	g.Expect(os.MkdirAll(filepath.Join(dockerDir, "db"), 0o700)).To(gomega.Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dockerDir, "db", "user"), []byte("u1\n\n"), 0o600)).To(gomega.Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dockerDir, "password"), []byte("p2"), 0o600)).To(gomega.Succeed())
+
+	p := props.NewProperties()
+	err := NewSecretDirPropertiesLoader(k8sDir, filepath.Join(k8sDir, "missing"), dockerDir).LoadProperties(ctx, p)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	for k, expect := range map[string]string{
+		"password": "p2",
+		"tls.cert": "c1",
+		"db.user":  "u1",
+	} {
+		v, err := p.Get(ctx, k)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(v).To(gomega.Equal(expect), k)
+		g.Expect(props.IsSecretKey(k)).To(gomega.BeTrue(), k)
+	}
+	_, err = p.Get(ctx, "..data.password")
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	origin, err := p.Origin(ctx, "tls.cert")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(origin.String()).To(gomega.Equal("runtime " + filepath.Join(k8sDir, "tls", "cert")))
+}
@@ -29,10 +29,29 @@ type extReader struct {
 	exts []string
 
 	fn func(data []byte) (map[string]any, error)
+	// located read the data and the line of keys in one pass, it's used if fn is nil.
+	located func(data []byte) (map[string]any, map[string]int, error)
 }
 
 func (r *extReader) Read(data []byte) (map[string]any, error) {
-	return r.fn(data)
+	if r.fn != nil {
+		return r.fn(data)
+	}
+
+	m, _, err := r.located(data)
+	return m, err
+}
+
+func (r *extReader) ReadLocated(data []byte) (map[string]any, map[string]int, error) {
+	if r.located != nil {
+		return r.located(data)
+	}
+
+	m, err := r.fn(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, map[string]int{}, nil
 }
 
 func (r *extReader) Match(filename string) error {
@@ -0,0 +1,46 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package reader
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+func jsonRead(data []byte) (map[string]any, error) {
+	m := make(map[string]any)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Keep the number literal, so the large integer won't lose precision
+	decoder.UseNumber()
+	if err := decoder.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return normalizeValue(m).(map[string]any), nil
+}
+
+func init() {
+	err := RegisterExtFileReader(jsonRead, ".json")
+	if err != nil {
+		panic(xerrors.Wrapf(err, "Register json reader"))
+	}
+}
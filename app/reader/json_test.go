@@ -0,0 +1,56 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package reader
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestJsonRead(t *testing.T) {
+	g := NewWithT(t)
+
+	m, err := jsonRead([]byte(`{"db": {"host": "localhost", "port": 3306, "ratio": 0.5, "replicas": [{"host": "r1"}, "r2"]}, "debug": true}`))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m).To(Equal(map[string]any{
+		"db": map[string]any{
+			"host":  "localhost",
+			"port":  "3306",
+			"ratio": "0.5",
+			"replicas": []any{
+				map[string]any{"host": "r1"},
+				"r2",
+			},
+		},
+		"debug": true,
+	}))
+
+	m, err = jsonRead([]byte(`{"a": null, "b": {"c": null}, "d": [null, "x"]}`))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m).To(Equal(map[string]any{
+		"a": "",
+		"b": map[string]any{"c": ""},
+		"d": []any{"", "x"},
+	}))
+
+	_, err = jsonRead([]byte(`{"a": [`))
+	g.Expect(err).To(HaveOccurred())
+}
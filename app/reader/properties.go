@@ -0,0 +1,175 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package reader
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// propertiesEntry is the key & value of logical line in .properties file.
+type propertiesEntry struct {
+	key   string
+	value string
+	line  int
+}
+
+// parseProperties parse the java-style .properties file, the key is kept as it is, so the
+// indexed key (e.g. 'servers[0].host') will be read as the flattened key of properties.
+// The format is:
+//  1. the line start with '#' or '!' is comment
+//  2. the key and value is separated by '=', ':' or whitespace
+//  3. the line end with '\\' will be continued with next line
+//  4. the escapes '\\t', '\\n', '\\r', '\\f', '\\uXXXX' is supported, and other escaped char is kept.
+//     The '\\$' is kept as it is, so the placeholder can still be escaped as '\\${x}' in value.
+func parseProperties(data []byte) ([]propertiesEntry, error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	ret := []propertiesEntry{}
+	for i := 0; i < len(lines); i++ {
+		start := i
+		logical := strings.TrimLeft(lines[i], " \t\f")
+		if logical == "" || logical[0] == '#' || logical[0] == '!' {
+			continue
+		}
+
+		for endWithContinuation(logical) && i+1 < len(lines) {
+			i++
+			logical = logical[:len(logical)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if endWithContinuation(logical) {
+			logical = logical[:len(logical)-1]
+		}
+
+		key, value := splitPropertiesLine(logical)
+		ukey, err := unescapeProperties(key)
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "Invalid key at line %d", start+1)
+		}
+		uvalue, err := unescapeProperties(value)
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "Invalid value at line %d", start+1)
+		}
+
+		ret = append(ret, propertiesEntry{
+			key:   ukey,
+			value: uvalue,
+			line:  start + 1,
+		})
+	}
+	return ret, nil
+}
+
+// endWithContinuation return true if the line end with odd number of '\\'.
+func endWithContinuation(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+func splitPropertiesLine(line string) (string, string) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+
+	key := line[:end]
+	value := strings.TrimLeft(line[end:], " \t\f")
+	if value != "" && (value[0] == '=' || value[0] == ':') {
+		value = strings.TrimLeft(value[1:], " \t\f")
+	}
+	return key, value
+}
+
+func unescapeProperties(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case '$':
+			b.WriteString("\\$")
+		case 'u':
+			if i+5 > len(s) {
+				return "", xerrors.Errorf("Malformed \\uxxxx encoding '%v'", s[i-1:])
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", xerrors.Errorf("Malformed \\uxxxx encoding '%v'", s[i-1:i+5])
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+func propertiesReadLocated(data []byte) (map[string]any, map[string]int, error) {
+	entries, err := parseProperties(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := make(map[string]any, len(entries))
+	lines := make(map[string]int, len(entries))
+	for _, e := range entries {
+		m[e.key] = e.value
+		lines[e.key] = e.line
+	}
+	return m, lines, nil
+}
+
+func init() {
+	err := RegisterReader(&extReader{
+		exts:    []string{".properties"},
+		located: propertiesReadLocated,
+	})
+	if err != nil {
+		panic(xerrors.Wrapf(err, "Register properties reader"))
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package reader
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPropertiesRead(t *testing.T) {
+	type testCase struct {
+		desp   string
+		data   string
+		expect map[string]any
+		err    string
+	}
+	testCases := []testCase{
+		{
+			desp: "separators and comments",
+			data: `# comment
+! another comment
+
+db.host = localhost
+db.port:3306
+db.user  root
+db.empty
+servers[0].host=s1
+`,
+			expect: map[string]any{
+				"db.host":         "localhost",
+				"db.port":         "3306",
+				"db.user":         "root",
+				"db.empty":        "",
+				"servers[0].host": "s1",
+			},
+		},
+		{
+			desp: "continuation lines",
+			data: "list = a,\\\n    b,\\\n    c\npath = c:\\\\\nnext = v\n",
+			expect: map[string]any{
+				"list": "a,b,c",
+				"path": "c:\\",
+				"next": "v",
+			},
+		},
+		{
+			desp: "escapes",
+			data: "a\\=b = x\\ty\\u0041\\z\r\nc\\ d=\\:e\n",
+			expect: map[string]any{
+				"a=b": "x\tyAz",
+				"c d": ":e",
+			},
+		},
+		{
+			desp: "escaped placeholder",
+			data: "a = \\${x}\nb = ${y}\n",
+			expect: map[string]any{
+				"a": "\\${x}",
+				"b": "${y}",
+			},
+		},
+		{
+			desp: "malformed unicode",
+			data: "a = ok\nb = \\u00zz\n",
+			err:  "Invalid value at line 2: Malformed \\\\uxxxx encoding",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m, _, err := propertiesReadLocated([]byte(tc.data))
+			if tc.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(MatchRegexp(tc.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(m).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestPropertiesReadLocated(t *testing.T) {
+	g := NewWithT(t)
+
+	_, lines, err := propertiesReadLocated([]byte("# comment\na = 1\nb = x,\\\n  y\nc = 2\n"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(lines).To(Equal(map[string]int{
+		"a": 2,
+		"b": 3,
+		"c": 5,
+	}))
+}
@@ -21,6 +21,9 @@
 package reader
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/anyvoxel/airmid/anvil/xerrors"
 )
 
@@ -36,6 +39,13 @@ type Reader interface {
 	Name() string
 }
 
+// Locator is the optional interface of Reader, which can report the line of keys.
+type Locator interface {
+	// ReadLocated will unmarshal the data and return the line of flattened keys in it,
+	// e.g. 'db.host', 'servers[0]'.
+	ReadLocated(data []byte) (map[string]any, map[string]int, error)
+}
+
 var (
 	readers = []Reader{}
 )
@@ -77,3 +87,50 @@ func Read(filename string, data []byte) (map[string]any, error) {
 
 	return nil, xerrors.Errorf("Cannot found reader for '%v'", filename)
 }
+
+// ReadLocated will unmarshal the data and return the line of flattened keys in it, the
+// empty lines will be returned if the reader of filename doesn't implement Locator.
+func ReadLocated(filename string, data []byte) (map[string]any, map[string]int, error) {
+	for _, r := range readers {
+		if err := r.Match(filename); err != nil {
+			continue
+		}
+
+		if l, ok := r.(Locator); ok {
+			return l.ReadLocated(data)
+		}
+		m, err := r.Read(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m, map[string]int{}, nil
+	}
+
+	return nil, nil, xerrors.Errorf("Cannot found reader for '%v'", filename)
+}
+
+// normalizeValue convert the decoded values which cannot be set to properties into string,
+// the null is converted to empty string as same as the JSON value in env.
+func normalizeValue(v any) any {
+	switch vv := v.(type) {
+	case nil:
+		return ""
+	case map[string]any:
+		for k, e := range vv {
+			vv[k] = normalizeValue(e)
+		}
+	case []any:
+		for i, e := range vv {
+			vv[i] = normalizeValue(e)
+		}
+	case []map[string]any:
+		for _, e := range vv {
+			normalizeValue(e)
+		}
+	case json.Number:
+		return vv.String()
+	case time.Time:
+		return vv.Format(time.RFC3339Nano)
+	}
+	return v
+}
@@ -128,3 +128,39 @@ func TestRead(t *testing.T) {
 		g.Expect(err).To(HaveOccurred())
 	})
 }
+
+func TestReadLocated(t *testing.T) {
+	g := NewWithT(t)
+
+	readers = []Reader{
+		&extReader{
+			exts: []string{"1"},
+			fn: func(data []byte) (map[string]any, error) {
+				return map[string]any{"k": "1"}, nil
+			},
+		},
+		&extReader{
+			exts: []string{"2"},
+			located: func(data []byte) (map[string]any, map[string]int, error) {
+				return map[string]any{"k": "2"}, map[string]int{"k": 1}, nil
+			},
+		},
+	}
+
+	m, lines, err := ReadLocated("x1", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m).To(Equal(map[string]any{"k": "1"}))
+	g.Expect(lines).To(BeEmpty())
+
+	m, lines, err = ReadLocated("x2", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m).To(Equal(map[string]any{"k": "2"}))
+	g.Expect(lines).To(Equal(map[string]int{"k": 1}))
+
+	m, err = Read("x2", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m).To(Equal(map[string]any{"k": "2"}))
+
+	_, _, err = ReadLocated("x3", nil)
+	g.Expect(err).To(MatchError("Cannot found reader for 'x3'"))
+}
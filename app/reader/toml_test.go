@@ -0,0 +1,60 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package reader
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTomlRead(t *testing.T) {
+	g := NewWithT(t)
+
+	m, err := tomlRead([]byte(`debug = true
+started = 2025-01-02T03:04:05Z
+
+[db]
+host = "localhost"
+port = 3306
+
+[[db.replicas]]
+host = "r1"
+
+[[db.replicas]]
+host = "r2"
+`))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m).To(Equal(map[string]any{
+		"debug":   true,
+		"started": "2025-01-02T03:04:05Z",
+		"db": map[string]any{
+			"host": "localhost",
+			"port": int64(3306),
+			"replicas": []map[string]any{
+				{"host": "r1"},
+				{"host": "r2"},
+			},
+		},
+	}))
+
+	_, err = tomlRead([]byte(`a = [`))
+	g.Expect(err).To(HaveOccurred())
+}
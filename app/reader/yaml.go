@@ -20,22 +20,66 @@
 package reader
 
 import (
-	"gopkg.in/yaml.v2"
+	"fmt"
+
+	"go.yaml.in/yaml/v3"
 
 	"github.com/anyvoxel/airmid/anvil/xerrors"
 )
 
-func yamlRead(data []byte) (map[string]any, error) {
+// yamlReadLocated parse the data into node tree once, the values are decoded from the
+// tree and the line of flattened keys is collected from it.
+func yamlReadLocated(data []byte) (map[string]any, map[string]int, error) {
+	doc := yaml.Node{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
 	m := make(map[string]any)
-	err := yaml.Unmarshal(data, &m)
-	if err != nil {
-		return nil, err
+	lines := map[string]int{}
+	if len(doc.Content) == 0 {
+		return m, lines, nil
+	}
+	if err := doc.Decode(&m); err != nil {
+		return nil, nil, err
+	}
+
+	for _, n := range doc.Content {
+		locateYAMLNode("", n, lines)
+	}
+	return normalizeValue(m).(map[string]any), lines, nil
+}
+
+//nolint:exhaustive
+func locateYAMLNode(key string, n *yaml.Node, lines map[string]int) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i].Value
+			if key != "" {
+				k = key + "." + k
+			}
+			lines[k] = n.Content[i].Line
+			locateYAMLNode(k, n.Content[i+1], lines)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			k := fmt.Sprintf("%s[%d]", key, i)
+			lines[k] = c.Line
+			locateYAMLNode(k, c, lines)
+		}
+	case yaml.AliasNode:
+		if n.Alias != nil {
+			locateYAMLNode(key, n.Alias, lines)
+		}
 	}
-	return m, nil
 }
 
 func init() {
-	err := RegisterExtFileReader(yamlRead, ".yaml", ".yml")
+	err := RegisterReader(&extReader{
+		exts:    []string{".yaml", ".yml"},
+		located: yamlReadLocated,
+	})
 	if err != nil {
 		panic(xerrors.Wrapf(err, "Register yaml reader"))
 	}
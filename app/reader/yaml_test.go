@@ -0,0 +1,79 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package reader
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestYamlReadLocated(t *testing.T) {
+	g := NewWithT(t)
+
+	m, lines, err := yamlReadLocated([]byte(`db:
+  host: localhost
+  replicas:
+    - host: r1
+    - r2
+base: &base
+  k: v
+derived: *base
+`))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(lines).To(Equal(map[string]int{
+		"db":                  1,
+		"db.host":             2,
+		"db.replicas":         3,
+		"db.replicas[0]":      4,
+		"db.replicas[0].host": 4,
+		"db.replicas[1]":      5,
+		"base":                6,
+		"base.k":              7,
+		"derived":             8,
+		"derived.k":           7,
+	}))
+	g.Expect(m).To(Equal(map[string]any{
+		"db": map[string]any{
+			"host": "localhost",
+			"replicas": []any{
+				map[string]any{"host": "r1"},
+				"r2",
+			},
+		},
+		"base":    map[string]any{"k": "v"},
+		"derived": map[string]any{"k": "v"},
+	}))
+
+	m, _, err = yamlReadLocated([]byte("a: ~\nb:\n  - null\n"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m).To(Equal(map[string]any{
+		"a": "",
+		"b": []any{""},
+	}))
+
+	m, lines, err = yamlReadLocated([]byte(""))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m).To(BeEmpty())
+	g.Expect(lines).To(BeEmpty())
+
+	_, _, err = yamlReadLocated([]byte(`a: [`))
+	g.Expect(err).To(HaveOccurred())
+}
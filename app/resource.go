@@ -21,9 +21,11 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log/slog"
 
+	"github.com/anyvoxel/airmid/anvil/xerrors"
 	"github.com/anyvoxel/airmid/app/reader"
 	"github.com/anyvoxel/airmid/ioc/props"
 	slogctx "github.com/veqryn/slog-context"
@@ -32,31 +34,80 @@ import (
 // Config holds the config resources.
 type config struct {
 	resourceLocator  ResourceLocator `airmid:"autowire:?"`
-	ConfigExtensions []string        `airmid:"value:${airmid.config.extensions:=.yaml,.yml}"`
+	ConfigExtensions []string        `airmid:"value:${airmid.config.extensions:=.yaml,.yml,.json,.toml,.properties}"`
 	ActiveProfiles   []string        `airmid:"value:${airmid.profiles.active:=}"`
+
+	// sourceNames is the names of loaded config file sources, it's used to replace them when reloading.
+	sourceNames []string
+	// fingerprint is the fingerprint of config files loaded by loadProperty
+	fingerprint string
 }
 
 func (*config) NewConfig() (*config, error) {
 	return &config{}, nil
 }
 
-//nolint:revive,cyclop
-func (c *config) loadProperty(ctx context.Context, p props.Properties) error {
-	resources := []Resource{}
+// configResource is the content of located config file.
+type configResource struct {
+	name       string
+	data       []byte
+	precedence props.Precedence
+}
+
+// loadProperty load the config files as property sources, the profile config file take higher
+// precedence than the base config file, and the later located file take higher priority.
+func (c *config) loadProperty(ctx context.Context, p props.ConfigurableProperties) error {
+	ress, err := c.readResources(ctx)
+	if err != nil {
+		return err
+	}
+
+	sources, err := parseResources(ctx, ress)
+	if err != nil {
+		return err
+	}
+
+	c.replaceSources(p, ress, sources)
+	c.fingerprint = fingerprintResources(ress)
+	return nil
+}
 
+// replaceSources add the new sources, and remove the loaded config file sources which are not
+// replaced. The new sources are added first, so the concurrent readers never see the properties
+// without config files.
+func (c *config) replaceSources(p props.ConfigurableProperties, ress []configResource, sources []props.PropertySource) {
+	names := make([]string, 0, len(sources))
+	added := map[string]struct{}{}
+	for i, source := range sources {
+		p.AddPropertySource(source, ress[i].precedence)
+		names = append(names, source.Name())
+		added[source.Name()] = struct{}{}
+	}
+
+	for _, name := range c.sourceNames {
+		if _, ok := added[name]; !ok {
+			p.RemovePropertySource(name)
+		}
+	}
+	c.sourceNames = names
+}
+
+// readResources locate & read all of the config files, which is ordered by priority ascending.
+//
+//nolint:revive,cyclop
+func (c *config) readResources(ctx context.Context) ([]configResource, error) {
 	slogctx.FromCtx(ctx).DebugContext(
 		ctx,
 		"Configuration file extensions supported",
 		slog.Any("ConfigExtensions", c.ConfigExtensions),
 	)
+	ret := []configResource{}
 	for _, ext := range c.ConfigExtensions {
-		filename := "application" + ext
-		ress, err := c.resourceLocator.Locate(filename)
+		ress, err := c.readResource(ctx, "application"+ext, props.PrecedenceConfigFile)
 		if err != nil {
-			return err
+			return nil, err
 		}
-
-		resources = append(resources, ress...)
+		ret = append(ret, ress...)
 	}
 
 	slogctx.FromCtx(ctx).DebugContext(
@@ -67,38 +118,83 @@ func (c *config) loadProperty(ctx context.Context, p props.Properties) error {
 	for _, profile := range c.ActiveProfiles {
 		for _, ext := range c.ConfigExtensions {
 			filename := "application-" + profile + ext
-			ress, err := c.resourceLocator.Locate(filename)
+			ress, err := c.readResource(ctx, filename, props.PrecedenceProfileConfigFile)
 			if err != nil {
-				return err
+				return nil, err
 			}
-
-			resources = append(resources, ress...)
+			ret = append(ret, ress...)
 		}
 	}
 
-	for _, res := range resources {
+	return ret, nil
+}
+
+func (c *config) readResource(
+	ctx context.Context, filename string, precedence props.Precedence) ([]configResource, error) {
+	ress, err := c.resourceLocator.Locate(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]configResource, 0, len(ress))
+	for _, res := range ress {
+		name := res.Name()
 		slogctx.FromCtx(ctx).DebugContext(
 			ctx,
 			"Loading configuration properties",
-			slog.String("FileName", res.Name()),
+			slog.String("FileName", name),
 		)
-		data, err := io.ReadAll(res)
+		data, err := readAndClose(res)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		objs, err := reader.Read(res.Name(), data)
+		ret = append(ret, configResource{
+			name:       name,
+			data:       data,
+			precedence: precedence,
+		})
+	}
+	return ret, nil
+}
+
+func readAndClose(res Resource) ([]byte, error) {
+	if c, ok := res.(io.Closer); ok {
+		defer c.Close() //nolint:errcheck
+	}
+
+	return io.ReadAll(res)
+}
+
+func parseResources(ctx context.Context, ress []configResource) ([]props.PropertySource, error) {
+	ret := make([]props.PropertySource, 0, len(ress))
+	for _, res := range ress {
+		source, err := res.parse(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		ret = append(ret, source)
+	}
+	return ret, nil
+}
 
-		for k, v := range objs {
-			err := p.Set(ctx, k, v)
-			if err != nil {
-				return err
-			}
-		}
+// parse read the resource as property source, which is named as 'file <name>' and
+// record the line of keys if the reader support it.
+func (r configResource) parse(ctx context.Context) (props.PropertySource, error) {
+	objs, lines, err := reader.ReadLocated(r.name, r.data)
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "Cannot read config file '%v'", r.name)
 	}
 
-	return nil
+	ctx = props.ContextWithLocation(ctx, func(key string) string {
+		if line, ok := lines[key]; ok {
+			return fmt.Sprintf("line %d", line)
+		}
+		return ""
+	})
+	source := props.NewPropertySource("file " + r.name)
+	if err := source.SetAll(ctx, objs); err != nil {
+		return nil, err
+	}
+	return source, nil
 }
@@ -21,13 +21,16 @@ package app
 
 import (
 	"context"
+	"errors"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 
-	propsmocks "github.com/anyvoxel/airmid/ioc/props/mocks"
+	"github.com/anyvoxel/airmid/ioc/props"
 )
 
 func TestConfigLoadProperty(t *testing.T) {
@@ -36,26 +39,30 @@ func TestConfigLoadProperty(t *testing.T) {
 
 		mockCtrl := gomock.NewController(t)
 		mockResourceLocator := NewMockResourceLocator(mockCtrl)
-		mockProps := propsmocks.NewMockProperties(mockCtrl)
+		p := props.NewProperties()
 
 		c := &config{
 			resourceLocator:  mockResourceLocator,
 			ConfigExtensions: []string{".yaml", ".yml"},
 			ActiveProfiles:   []string{"test"},
 		}
-		data := []byte(`k1: v1
-k2: v2`)
-		mockResource := NewMockResource(mockCtrl)
-		mockResource.EXPECT().Read(gomock.Any()).DoAndReturn(
-			func(p []byte) (n int, err error) {
-				copy(p, data)
-				return len(data), io.EOF
-			},
-		)
-		mockResource.EXPECT().Name().Return("application.yaml").Times(2)
+		newResource := func(name string, data []byte) Resource {
+			mockResource := NewMockResource(mockCtrl)
+			mockResource.EXPECT().Read(gomock.Any()).DoAndReturn(
+				func(p []byte) (n int, err error) {
+					copy(p, data)
+					return len(data), io.EOF
+				},
+			)
+			mockResource.EXPECT().Name().Return(name).Times(1)
+			return mockResource
+		}
 
 		mockResourceLocator.EXPECT().Locate(gomock.Eq("application.yaml")).Times(1).Return(
-			[]Resource{mockResource},
+			[]Resource{newResource("application.yaml", []byte(`k1: v1
+k2: v2
+k3:
+  - v3`))},
 			nil,
 		)
 		mockResourceLocator.EXPECT().Locate(gomock.Eq("application.yml")).Times(1).Return(
@@ -67,13 +74,86 @@ k2: v2`)
 			nil,
 		)
 		mockResourceLocator.EXPECT().Locate(gomock.Eq("application-test.yml")).Times(1).Return(
-			nil,
+			[]Resource{newResource("application-test.yml", []byte(`k2: v22`))},
 			nil,
 		)
-		mockProps.EXPECT().Set(context.Background(), gomock.Eq("k1"), gomock.Eq("v1")).Times(1).Return(nil)
-		mockProps.EXPECT().Set(context.Background(), gomock.Eq("k2"), gomock.Eq("v2")).Times(1).Return(nil)
 
-		err := c.loadProperty(context.Background(), mockProps)
+		err := c.loadProperty(context.Background(), p)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		v, err := p.Get(context.Background(), "k1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v).To(Equal("v1"))
+		v, err = p.Get(context.Background(), "k2")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v).To(Equal("v22"))
+
+		origin, err := p.Origin(context.Background(), "k2")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(origin.String()).To(Equal("file application-test.yml line 1"))
+		origin, err = p.Origin(context.Background(), "k3")
 		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(origin.String()).To(Equal("file application.yaml line 4"))
+	})
+
+	t.Run("multiple formats", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		files := map[string]string{
+			"application.json":        `{"k1": "json", "k2": "json", "db": {"port": 3306}}`,
+			"application.toml":        "k2 = \"toml\"\nk3 = \"toml\"\n",
+			"application.properties":  "# comment\nk3 = properties\nservers[0].host = s1\n",
+			"application-test.toml":   "k1 = \"profile\"\n",
+			"application-test.unknow": "k1 = unknow\n",
+		}
+		for name, content := range files {
+			g.Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)).To(Succeed())
+		}
+
+		p := props.NewProperties()
+		c := &config{
+			resourceLocator: &localResourceLocator{
+				configDir: []string{dir},
+			},
+			ConfigExtensions: []string{".json", ".toml", ".properties"},
+			ActiveProfiles:   []string{"test"},
+		}
+		err := c.loadProperty(context.Background(), p)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for k, expect := range map[string]string{
+			"k1":              "profile",
+			"k2":              "toml",
+			"k3":              "properties",
+			"db.port":         "3306",
+			"servers[0].host": "s1",
+		} {
+			v, err := p.Get(context.Background(), k)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(v).To(Equal(expect), k)
+		}
+
+		origin, err := p.Origin(context.Background(), "k3")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(origin.String()).To(Equal("file " + filepath.Join(dir, "application.properties") + " line 2"))
+	})
+
+	t.Run("locate failed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mockCtrl := gomock.NewController(t)
+		mockResourceLocator := NewMockResourceLocator(mockCtrl)
+		c := &config{
+			resourceLocator:  mockResourceLocator,
+			ConfigExtensions: []string{".yaml"},
+		}
+		mockResourceLocator.EXPECT().Locate(gomock.Eq("application.yaml")).Times(1).Return(
+			nil,
+			errors.New("locate failed"),
+		)
+
+		err := c.loadProperty(context.Background(), props.NewProperties())
+		g.Expect(err).To(MatchError("locate failed"))
 	})
 }
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"sort"
+	"time"
+
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+const (
+	// secretDirsPropertyKey is the directories of mounted secret files, e.g. '/run/secrets'.
+	secretDirsPropertyKey = "airmid.secret.dirs"
+
+	// secretDirSourceName is the name of property source which hold the secret files.
+	secretDirSourceName = "secrets"
+)
+
+// loadSecretDirSource return the property source which hold the files in secret dirs.
+func loadSecretDirSource(ctx context.Context, dirs []string) (props.PropertySource, error) {
+	source := props.NewPropertySource(secretDirSourceName)
+	if err := NewSecretDirPropertiesLoader(dirs...).LoadProperties(ctx, source); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// secretDirWatcher poll the secret dirs, and replace the secret property source when the
+// secret files are rotated.
+type secretDirWatcher struct {
+	dirs     []string      `airmid:"value:${airmid.secret.dirs:=}"`
+	enabled  bool          `airmid:"value:${airmid.secret.watch.enabled:=false}"`
+	interval time.Duration `airmid:"value:${airmid.secret.watch.interval:=10s}" validate:"min=1ms"`
+
+	app Application
+
+	poller  poller
+	changes debouncer
+}
+
+var (
+	_ Runner           = (*secretDirWatcher)(nil)
+	_ ApplicationAware = (*secretDirWatcher)(nil)
+)
+
+func (w *secretDirWatcher) SetApplication(application Application) {
+	w.app = application
+}
+
+// Run implement Runner.Run, it will start polling in background if the watcher is enabled. The
+// secret source loaded at startup is the baseline, so the rotation after loading is reloaded too.
+func (w *secretDirWatcher) Run(ctx context.Context) {
+	if !w.enabled || len(w.dirs) == 0 {
+		return
+	}
+
+	w.changes = debouncer{applied: w.loadedFingerprint()}
+	w.poller.start(ctx, w.interval, w.poll)
+}
+
+// Stop implement Runner.Stop, it will wait until the polling exited.
+func (w *secretDirWatcher) Stop(ctx context.Context) {
+	w.poller.stop(ctx)
+}
+
+// loadedFingerprint return the fingerprint of secret source in properties, the empty source is
+// used if it's not loaded.
+func (w *secretDirWatcher) loadedFingerprint() string {
+	for _, source := range w.app.PropertySources() {
+		if source.Name() == secretDirSourceName {
+			return fingerprintSource(source)
+		}
+	}
+	return fingerprintSource(props.NewPropertySource(secretDirSourceName))
+}
+
+// poll read the secret dirs, and replace the secret source if it's changed, it return the
+// duration to wait before next poll.
+func (w *secretDirWatcher) poll(ctx context.Context) time.Duration {
+	source, err := loadSecretDirSource(ctx, w.dirs)
+	if err != nil {
+		slogctx.FromCtx(ctx).ErrorContext(
+			ctx,
+			"secret watcher cannot read secret dirs, keep the current properties",
+			slog.Any("Error", err),
+		)
+		return w.interval
+	}
+
+	apply, wait := w.changes.observe(fingerprintSource(source), time.Now(), w.interval)
+	if !apply {
+		return wait
+	}
+
+	err = w.app.UpdateProperties(ctx, func(p props.ConfigurableProperties) error {
+		p.AddPropertySource(source, props.PrecedenceSecretDir)
+		return nil
+	})
+	if err != nil {
+		slogctx.FromCtx(ctx).ErrorContext(
+			ctx,
+			"secret watcher cannot refresh properties",
+			slog.Any("Error", err),
+		)
+		return wait
+	}
+
+	slogctx.FromCtx(ctx).InfoContext(
+		ctx,
+		"secret watcher reload secret files success",
+	)
+	return wait
+}
+
+func fingerprintSource(source props.PropertySource) string {
+	keys := source.Keys("")
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		v, _ := source.Lookup(k)
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+func TestSecretDirWatcherPoll(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeFile := func(name string, content string) {
+		g.Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)).To(Succeed())
+	}
+	app := NewApplication().(*airmidApplication)
+	getPassword := func() any {
+		v, err := app.Get(ctx, "db.password")
+		g.Expect(err).ToNot(HaveOccurred())
+		return v
+	}
+
+	g.Expect(os.Mkdir(filepath.Join(dir, "db"), 0o700)).To(Succeed())
+	writeFile("db/password", "p1\n")
+	source, err := loadSecretDirSource(ctx, []string{dir})
+	g.Expect(err).ToNot(HaveOccurred())
+	app.AddPropertySource(source, props.PrecedenceSecretDir)
+	g.Expect(getPassword()).To(Equal("p1"))
+
+	w := &secretDirWatcher{
+		dirs:    []string{dir},
+		enabled: true,
+		app:     app,
+	}
+	w.changes = debouncer{applied: w.loadedFingerprint()}
+
+	// The unchanged secrets won't be reloaded
+	w.poll(ctx)
+	g.Expect(app.PropertySources()[1]).To(BeIdenticalTo(source))
+
+	// The rotated secret is reloaded
+	writeFile("db/password", "p2\n")
+	w.poll(ctx)
+	g.Expect(getPassword()).To(Equal("p2"))
+
+	// The unreadable dir won't change the current properties
+	g.Expect(os.Chmod(filepath.Join(dir, "db"), 0o000)).To(Succeed())
+	defer os.Chmod(filepath.Join(dir, "db"), 0o700) //nolint:errcheck
+	if _, err := os.ReadDir(filepath.Join(dir, "db")); err != nil {
+		w.poll(ctx)
+		g.Expect(getPassword()).To(Equal("p2"))
+	}
+}
+
+func TestSecretDirWatcherRunAndStop(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "token"), []byte("t1"), 0o600)).To(Succeed())
+
+	app := NewApplication().(*airmidApplication)
+	source, err := loadSecretDirSource(ctx, []string{dir})
+	g.Expect(err).ToNot(HaveOccurred())
+	app.AddPropertySource(source, props.PrecedenceSecretDir)
+
+	// The disabled watcher do nothing
+	w := &secretDirWatcher{dirs: []string{dir}, app: app}
+	w.Run(ctx)
+	w.Stop(ctx)
+
+	changedCh := make(chan []string, 1)
+	unsubscribe := Subscribe(app, func(_ context.Context, ev PropertiesChangedEvent) {
+		changedCh <- ev.Keys
+	})
+	defer unsubscribe()
+
+	// The rotation between loading and Run is reloaded too
+	g.Expect(os.WriteFile(filepath.Join(dir, "token"), []byte("t2"), 0o600)).To(Succeed())
+	w = &secretDirWatcher{
+		dirs:     []string{dir},
+		enabled:  true,
+		interval: 10 * time.Millisecond,
+		app:      app,
+	}
+	w.Run(ctx)
+
+	g.Eventually(changedCh).Should(Receive(Equal([]string{"token"})))
+	w.Stop(ctx)
+
+	v, err := app.Get(ctx, "token")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(v).To(Equal("t2"))
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+	"github.com/anyvoxel/airmid/ioc"
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+const (
+	// StrictModeIgnore ignore the unknown property keys.
+	StrictModeIgnore = "ignore"
+	// StrictModeWarn log the unknown property keys as warning.
+	StrictModeWarn = "warn"
+	// StrictModeFail log the unknown property keys and fail the startup.
+	StrictModeFail = "fail"
+)
+
+// builtinKnownKeys is the keys which are read without properties, e.g. AIRMID_INCLUDE_ENV_PATTERNS.
+var builtinKnownKeys = []string{
+	"include.env.patterns",
+	"exclude.env.patterns",
+}
+
+// checkUnknownKeys report the loaded property keys which are not consumed by any bean, it
+// should be called after the singletons are instantiated.
+func (a *airmidApplication) checkUnknownKeys(ctx context.Context) error {
+	mode := a.props.strictMode
+	switch mode {
+	case StrictModeIgnore:
+		return nil
+	case StrictModeWarn, StrictModeFail:
+	default:
+		return xerrors.Errorf("Invalid strict mode '%v', it must be '%v', '%v' or '%v'",
+			mode, StrictModeIgnore, StrictModeWarn, StrictModeFail)
+	}
+
+	unknown := []string{}
+	for _, key := range a.unknownKeys(a.props.strictIgnore) {
+		origin, err := a.Origin(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		slogctx.FromCtx(ctx).WarnContext(
+			ctx,
+			"unknown property key",
+			slog.String("Key", key),
+			slog.String("Origin", origin.String()),
+		)
+		unknown = append(unknown, key+" ("+origin.String()+")")
+	}
+
+	if mode == StrictModeFail && len(unknown) > 0 {
+		return xerrors.Errorf("Unknown property keys: %v", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// unknownKeys return the sorted keys which are loaded from sources but not consumed, the key is known if:
+//  1. it is read by Get, e.g. the injected field of created bean
+//  2. it is consumed by the property field of bean which is not created, e.g. the lazy bean
+//  3. it is under the config prefix of bean which is not created
+//  4. it is under the ignore prefixes or builtin known keys.
+//
+// The keys are compared in relaxed binding, e.g. 'db.max-pool' is under the prefix 'db.maxPool'.
+// The runtime source is skipped, and the keys which are read after the singletons are instantiated
+// (e.g. by Get in Runner.Run) are reported as unknown, they should be added to the ignore prefixes.
+func (a *airmidApplication) unknownKeys(ignore []string) []string {
+	loaded := map[string]struct{}{}
+	for _, s := range a.PropertySources() {
+		if s.Name() == props.RuntimePropertySourceName {
+			continue
+		}
+		for _, k := range s.Keys("") {
+			loaded[k] = struct{}{}
+		}
+	}
+
+	prefixes := append(append([]string{}, ignore...), builtinKnownKeys...)
+	for _, d := range ioc.PropertyDescriptors(a) {
+		if !d.Config || !a.isSubtreeConsumed(loaded, d.Key) {
+			prefixes = append(prefixes, d.Key)
+		}
+	}
+
+	ret := []string{}
+	for k := range loaded {
+		if a.IsConsumed(k) || hasKeyPrefix(k, prefixes) {
+			continue
+		}
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// isSubtreeConsumed return true if any loaded key under prefix is consumed, which means the
+// config field has been bound, so the unconsumed keys under it are unknown.
+func (a *airmidApplication) isSubtreeConsumed(loaded map[string]struct{}, prefix string) bool {
+	for k := range loaded {
+		if hasKeyPrefix(k, []string{prefix}) && a.IsConsumed(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasKeyPrefix return true if key is any of prefixes or the sub key of them in relaxed binding.
+func hasKeyPrefix(key string, prefixes []string) bool {
+	key = props.CanonicalKey(key)
+	for _, prefix := range prefixes {
+		prefix = props.CanonicalKey(prefix)
+		if prefix == "" {
+			continue
+		}
+		if key == prefix || strings.HasPrefix(key, prefix+".") || strings.HasPrefix(key, prefix+"[") {
+			return true
+		}
+	}
+	return false
+}
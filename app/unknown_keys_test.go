@@ -0,0 +1,117 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/anyvoxel/airmid/ioc"
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+type testUnknownKeysDBConfig struct {
+	Host string
+}
+
+type testUnknownKeysBean struct {
+	duration time.Duration            `airmid:"value:${test.shutdown.duration:=1s}"`
+	hosts    []string                 `airmid:"value:${test.hosts}"`
+	db       *testUnknownKeysDBConfig `airmid:"config:test.db"`
+}
+
+type testUnknownKeysLazyBean struct {
+	port  int                      `airmid:"value:${test.lazy.port:=80}"`
+	cache *testUnknownKeysDBConfig `airmid:"config:test.cache"`
+}
+
+func TestCheckUnknownKeys(t *testing.T) {
+	ctx := context.Background()
+	newApp := func(g *WithT) *airmidApplication {
+		a := NewApplication().(*airmidApplication)
+		g.Expect(a.RegisterBeanDefinition("b1", ioc.MustNewBeanDefinition(
+			reflect.TypeOf((*testUnknownKeysBean)(nil)),
+		))).To(Succeed())
+		g.Expect(a.RegisterBeanDefinition("b2", ioc.MustNewBeanDefinition(
+			reflect.TypeOf((*testUnknownKeysLazyBean)(nil)),
+			ioc.WithLazyMode(),
+		))).To(Succeed())
+
+		source := props.NewPropertySource("file application.yml")
+		lines := map[string]string{"test.shutdown.duraton": "line 3", "test.db.hostt": "line 5"}
+		g.Expect(source.Set(props.ContextWithLocation(ctx, func(k string) string {
+			return lines[k]
+		}), "test", map[string]any{
+			"shutdown":  map[string]any{"duration": "2s", "duraton": "3s"},
+			"hosts":     []any{"h1", "h2"},
+			"db":        map[string]any{"host": "h", "hostt": "x"},
+			"lazy":      map[string]any{"port": "81"},
+			"lazy-port": "82",
+			"cache":     map[string]any{"host": "c", "other": "o"},
+			"Cache":     map[string]any{"max-size": "1"},
+			"ignored":   map[string]any{"k": "v"},
+		})).To(Succeed())
+		a.AddPropertySource(source, props.PrecedenceConfigFile)
+		g.Expect(a.Set(ctx, "test.runtime", "v")).To(Succeed())
+
+		g.Expect(a.PreInstantiateSingletons(ctx)).To(Succeed())
+		return a
+	}
+
+	t.Run("unknown keys", func(t *testing.T) {
+		g := NewWithT(t)
+		a := newApp(g)
+
+		g.Expect(a.unknownKeys([]string{"test.ignored"})).To(Equal([]string{
+			"test.db.hostt",
+			"test.shutdown.duraton",
+		}))
+		g.Expect(a.unknownKeys([]string{"testIgnored"})).To(Equal([]string{
+			"test.db.hostt",
+			"test.shutdown.duraton",
+		}))
+	})
+
+	t.Run("strict modes", func(t *testing.T) {
+		g := NewWithT(t)
+		a := newApp(g)
+
+		a.props = &airmidApplicationProps{strictMode: StrictModeIgnore}
+		g.Expect(a.checkUnknownKeys(ctx)).To(Succeed())
+
+		a.props = &airmidApplicationProps{strictMode: StrictModeWarn}
+		g.Expect(a.checkUnknownKeys(ctx)).To(Succeed())
+
+		a.props = &airmidApplicationProps{strictMode: StrictModeFail, strictIgnore: []string{"test.ignored"}}
+		g.Expect(a.checkUnknownKeys(ctx)).To(MatchError("Unknown property keys: " +
+			"test.db.hostt (file application.yml line 5), " +
+			"test.shutdown.duraton (file application.yml line 3)"))
+
+		a.props = &airmidApplicationProps{strictMode: StrictModeFail, strictIgnore: []string{"test"}}
+		g.Expect(a.checkUnknownKeys(ctx)).To(Succeed())
+
+		a.props = &airmidApplicationProps{strictMode: "panic"}
+		g.Expect(a.checkUnknownKeys(ctx)).To(MatchError("Invalid strict mode 'panic', it must be 'ignore', 'warn' or 'fail'"))
+	})
+}
@@ -21,12 +21,16 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"reflect"
 	"runtime"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/anyvoxel/airmid/anvil"
 	"github.com/anyvoxel/airmid/anvil/xerrors"
 	"github.com/anyvoxel/airmid/ioc"
 	"github.com/anyvoxel/airmid/ioc/props"
@@ -41,6 +45,8 @@ type Application interface {
 	// 3. initialize application's component, such as gopool、logger、metrics
 	// 4. start all AppRunner
 	// 5. Waiting for shutdown signals
+	// If the '--help' flag or 'airmid.help' property is specified, it will print the properties
+	// consumed by beans and return after step 2.
 	Run(ctx context.Context, opts ...Option) error
 
 	// Shutdown will stop the application, the application will start graceful shutdown progress:
@@ -52,7 +58,16 @@ type Application interface {
 	// Submit will async run task in another goroutine
 	Submit(task func()) error
 
-	ApplicationEventPublisher
+	// UpdateProperties will update the properties with fn (e.g. set value, replace source), then
+	// re-inject the refreshable fields and publish PropertiesChangedEvent with the changed keys.
+	// Nothing is refreshed or published if no key is changed. The concurrent updates are serialized,
+	// but the events may be published in different order.
+	UpdateProperties(ctx context.Context, fn func(p props.ConfigurableProperties) error) error
+
+	// Args return the positional args which are not parsed as flags, e.g. the args after '--'.
+	Args() []string
+
+	ApplicationEventMulticaster
 	ioc.BeanFactory
 }
 
@@ -61,11 +76,24 @@ type airmidApplication struct {
 	exitChan        chan struct{}
 
 	ioc.BeanFactory
-	listenerInvoker []ListenerInvoker
+	// listenerInvoker is protected by listenerMu and replaced by the updated copy on write,
+	// so the listeners can be added & removed while publishing events. It's sorted by priority.
+	listenerInvoker []*registeredListenerInvoker
+	listenerMu      sync.RWMutex
+	listenerSeq     uint64
+	// listenerCache is the map from concrete event type to the matched invokers of listenerInvoker,
+	// it's replaced with listenerInvoker, so the cached invokers are never stale.
+	listenerCache *sync.Map
 
 	// Use another struct to store the props, so we can autowire it
 	props *airmidApplicationProps
 
+	// args is the positional command line args
+	args []string
+
+	// updateMu serialize the property updates, so the snapshot, diff & refresh won't be interleaved
+	updateMu sync.Mutex
+
 	// TODO: change this to bootstrap config?
 	appConfig       *config
 	shutdownManager ShutdownManager
@@ -73,6 +101,8 @@ type airmidApplication struct {
 	gpool interface {
 		Submit(func()) error
 	}
+	// eventExecutor deliver the events to async listeners, the gpool is used if it's nil
+	eventExecutor *asyncEventExecutor
 }
 
 type airmidApplicationProps struct {
@@ -80,6 +110,14 @@ type airmidApplicationProps struct {
 	runnerCompositor *RunnerCompositor `airmid:"autowire:?"`
 
 	shutdownDuration time.Duration `airmid:"value:${airmid.shutdown.duration:=30s}"`
+
+	// strictMode is the mode to handle the unknown property keys, it must be 'ignore', 'warn' or 'fail'
+	strictMode string `airmid:"value:${airmid.config.strict.mode:=ignore}"`
+	// strictIgnore is the key prefixes which won't be reported as unknown
+	strictIgnore []string `airmid:"value:${airmid.config.strict.ignore:=}"`
+
+	// eventErrorPolicy is the policy to handle the listener error, it must be 'stop', 'continue' or 'collect'
+	eventErrorPolicy string `airmid:"value:${airmid.event.error.policy:=collect}"`
 }
 
 // NewApplication return the application.
@@ -89,11 +127,12 @@ func NewApplication() Application {
 			&loggerStartupHandler{},
 			&metricsStartupHandler{},
 			&gpoolStartupHandler{},
+			&eventExecutorStartupHandler{},
 			&shutdownStartupHandler{},
 		},
 		exitChan:        make(chan struct{}),
 		BeanFactory:     ioc.NewBeanFactory(),
-		listenerInvoker: make([]ListenerInvoker, 0),
+		listenerInvoker: make([]*registeredListenerInvoker, 0),
 		props:           &airmidApplicationProps{},
 		appConfig:       &config{},
 	}
@@ -115,6 +154,14 @@ func (a *airmidApplication) registerAppBeanDefinitions() error {
 			reflect.TypeOf((*airmidApplicationProps)(nil)),
 			ioc.WithLazyMode(),
 		),
+		"airmid.app.config.watcher": ioc.MustNewBeanDefinition(
+			reflect.TypeOf((*configWatcher)(nil)),
+			ioc.WithLazyMode(),
+		),
+		"airmid.app.secret.watcher": ioc.MustNewBeanDefinition(
+			reflect.TypeOf((*secretDirWatcher)(nil)),
+			ioc.WithLazyMode(),
+		),
 		"airmid.app.runner.compositor": ioc.MustNewBeanDefinition(
 			reflect.TypeOf((*RunnerCompositor)(nil)),
 			ioc.WithLazyMode(),
@@ -164,29 +211,28 @@ func (a *airmidApplication) runBeforeStartRunner(ctx context.Context, opt *optio
 	return nil
 }
 
-func (a *airmidApplication) loadProperties(ctx context.Context) (err error) {
+func (a *airmidApplication) loadProperties(ctx context.Context, opt *option) (err error) {
 	// First, we load the env & flags property, so user can
 	// configuration some application's setting.
-	if err := a.loadPropsFromEnvAndFlags(ctx, a); err != nil {
+	if err := a.loadPropsFromEnvAndFlags(ctx, a, opt); err != nil {
 		return err
 	}
 
-	a.appConfig, err = ioc.GetBean[*config](ctx, a, "airmid.app.config")
-	if err != nil {
+	// The encrypted values are decrypted when they are read, so the key must be registered
+	// before any of them is read.
+	if err := registerDecryptor(ctx, a); err != nil {
 		return err
 	}
 
-	// Second, we load the config file's property, base on
-	// env & flags property.
-	err = a.appConfig.loadProperty(ctx, a)
+	a.appConfig, err = ioc.GetBean[*config](ctx, a, "airmid.app.config")
 	if err != nil {
 		return err
 	}
 
-	// At last, we reload env & flags property, so env & flags
-	// property can overwrite config file's setting, and take
-	// high priority.
-	return a.loadPropsFromEnvAndFlags(ctx, a)
+	// Second, we load the config file's property, base on
+	// env & flags property. The config file take lower precedence,
+	// so env & flags property can overwrite config file's setting.
+	return a.appConfig.loadProperty(ctx, a)
 }
 
 func (a *airmidApplication) Run(ctx context.Context, opts ...Option) (err error) {
@@ -209,11 +255,19 @@ func (a *airmidApplication) Run(ctx context.Context, opts ...Option) (err error)
 	a.AddBeanPostProcessor(&ApplicationAwareProcessor{app: a})
 	a.AddBeanPostProcessor(appRunnerCompoistorProcessor)
 
-	err = a.loadProperties(ctx)
+	err = a.loadProperties(ctx, opt)
 	if err != nil {
 		return err
 	}
 
+	help, err := a.isHelpRequested(ctx)
+	if err != nil {
+		return err
+	}
+	if help {
+		return a.printHelp(ctx, opt.helpOutput)
+	}
+
 	err = a.runAfterLoadProps(ctx, opt)
 	if err != nil {
 		return err
@@ -224,6 +278,11 @@ func (a *airmidApplication) Run(ctx context.Context, opts ...Option) (err error)
 		return err
 	}
 
+	err = validateEventErrorPolicy(a.props.eventErrorPolicy)
+	if err != nil {
+		return err
+	}
+
 	err = a.runBeforeStartRunner(ctx, opt)
 	if err != nil {
 		return err
@@ -234,6 +293,11 @@ func (a *airmidApplication) Run(ctx context.Context, opts ...Option) (err error)
 		return err
 	}
 
+	err = a.checkUnknownKeys(ctx)
+	if err != nil {
+		return err
+	}
+
 	a.props.runnerCompositor.appRunnerNames = appRunnerCompoistorProcessor.appRunnerNames
 	a.props.runnerCompositor.Run(ctx)
 
@@ -245,11 +309,96 @@ func (a *airmidApplication) Run(ctx context.Context, opts ...Option) (err error)
 	return nil
 }
 
-func (*airmidApplication) loadPropsFromEnvAndFlags(ctx context.Context, p props.Properties) error {
-	epl := NewEnvPropertiesLoader("AIRMID_", DefaultEnvKeyConvertFunc)
-	apl := NewOptionArgsPropertiesLoader()
-	loaders := NewPropertiesLoaders()
-	return loaders.Add(epl).Add(apl).LoadProperties(ctx, p)
+func (a *airmidApplication) loadPropsFromEnvAndFlags(
+	ctx context.Context,
+	p props.ConfigurableProperties,
+	opt *option,
+) error {
+	envSource := props.NewPropertySource("env")
+	envLoader := NewEnvPropertiesLoader("AIRMID_", DefaultEnvKeyConvertFunc, WithEnvListSeparator(opt.separator()))
+	err := envLoader.LoadProperties(ctx, envSource)
+	if err != nil {
+		return err
+	}
+	p.AddPropertySource(envSource, props.PrecedenceEnv)
+
+	flagsSource := props.NewPropertySource("flags")
+	argsLoader := newOptionArgsPropertiesLoader(opt.argsOptions()...)
+	err = argsLoader.LoadProperties(ctx, flagsSource)
+	if err != nil {
+		return err
+	}
+	p.AddPropertySource(flagsSource, props.PrecedenceFlags)
+	a.args = argsLoader.positional
+
+	// The dotenv paths can be specified by env & flags, so it must be loaded after them.
+	var paths []string
+	_, err = p.Get(ctx, "airmid.dotenv.paths", props.WithDefault(".env"), props.WithTarget(&paths))
+	if err != nil {
+		return err
+	}
+	dotenvSource := props.NewPropertySource("dotenv")
+	dotenvLoader := newDotenvPropertiesLoader("AIRMID_", DefaultEnvKeyConvertFunc, opt.separator(), paths...)
+	err = dotenvLoader.LoadProperties(ctx, dotenvSource)
+	if err != nil {
+		return err
+	}
+	p.AddPropertySource(dotenvSource, props.PrecedenceDotenv)
+
+	var secretDirs []string
+	_, err = p.Get(ctx, secretDirsPropertyKey, props.WithDefault(""), props.WithTarget(&secretDirs))
+	if err != nil {
+		return err
+	}
+	secretSource, err := loadSecretDirSource(ctx, secretDirs)
+	if err != nil {
+		return err
+	}
+	p.AddPropertySource(secretSource, props.PrecedenceSecretDir)
+	return nil
+}
+
+func (a *airmidApplication) Args() []string {
+	return a.args
+}
+
+func (a *airmidApplication) UpdateProperties(
+	ctx context.Context, fn func(p props.ConfigurableProperties) error) error {
+	keys, err := a.updateProperties(ctx, fn)
+	if len(keys) == 0 {
+		return err
+	}
+
+	// The properties is changed even if the refresh failed, so we always publish the event. It's
+	// published without lock, so the listeners can update the properties too.
+	return errors.Join(err, a.PublishEvent(ctx, PropertiesChangedEvent{
+		ApplicationEvent: NewDefaultApplicationEvent(a),
+		Keys:             keys,
+	}))
+}
+
+// updateProperties apply fn and refresh the beans under updateMu, it return the changed keys.
+func (a *airmidApplication) updateProperties(
+	ctx context.Context, fn func(p props.ConfigurableProperties) error) ([]string, error) {
+	a.updateMu.Lock()
+	defer a.updateMu.Unlock()
+
+	old := a.Snapshot()
+	if err := fn(a); err != nil {
+		return nil, err
+	}
+
+	keys := props.ChangedKeys(old, a.Snapshot())
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	slogctx.FromCtx(ctx).InfoContext(
+		ctx,
+		"properties changed",
+		slog.Any("Keys", keys),
+	)
+	return keys, a.RefreshProperties(ctx)
 }
 
 func (a *airmidApplication) Shutdown() {
@@ -291,15 +440,181 @@ func (a *airmidApplication) shutdownWithMessage(msg string) {
 		)
 	}
 
+	if a.eventExecutor != nil {
+		if err := a.eventExecutor.Close(ctx); err != nil {
+			slogctx.FromCtx(ctx).ErrorContext(
+				ctx,
+				"Async event executor cannot handle all queued events",
+				slog.Any("Error", err),
+			)
+		}
+	}
+
 	close(a.exitChan)
 }
 
-func (a *airmidApplication) PublishEvent(ctx context.Context, event ApplicationEvent) {
-	for _, invoker := range a.listenerInvoker {
-		invoker.Invoke(ctx, event)
+// PublishEvent notify the listeners by priority, the condition check & sync listener are executed with
+// anvil.SafeRunWithError, so the panic is isolated and handled as error of listener.
+func (a *airmidApplication) PublishEvent(ctx context.Context, event ApplicationEvent) error {
+	invokers := a.matchedListenerInvokers(event)
+
+	policy := EventErrorPolicyCollect
+	if a.props != nil && a.props.eventErrorPolicy != "" {
+		policy = a.props.eventErrorPolicy
+	}
+
+	var errs []error
+	for _, invoker := range invokers {
+		err := anvil.SafeRunWithError(ctx, func(ctx context.Context) error {
+			return invoker.Invoke(ctx, event)
+		})
+		if err == nil {
+			continue
+		}
+
+		switch policy {
+		case EventErrorPolicyStop:
+			return err
+		case EventErrorPolicyContinue:
+			slogctx.FromCtx(ctx).ErrorContext(
+				ctx, "Listener failed to handle event",
+				slog.Any("Event", event),
+				slog.Any("Error", err),
+			)
+		default:
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// registeredListenerInvoker is the added invoker, it's used to remove the invoker by identity
+// because the invoker may be not comparable.
+type registeredListenerInvoker struct {
+	ListenerInvoker
+	listenerOption
+
+	// seq is the added order of invoker, which is used to sort the invokers with same priority
+	seq uint64
+}
+
+// Invoke notify the invoker if the condition is satisfied.
+func (i *registeredListenerInvoker) Invoke(ctx context.Context, event ApplicationEvent) error {
+	if i.condition != nil && !i.condition(ctx, event) {
+		return nil
+	}
+	return i.ListenerInvoker.Invoke(ctx, event)
+}
+
+// supportsEventType report whether the invoker may handle the event of typ, the invoker which
+// doesn't implement eventTypeMatcher is treated as supporting all types.
+func (i *registeredListenerInvoker) supportsEventType(typ reflect.Type) bool {
+	if m, ok := i.ListenerInvoker.(eventTypeMatcher); ok {
+		return m.supportsEventType(typ)
+	}
+	return true
+}
+
+// before report whether i should be notified before o, the invoker without priority is the last.
+func (i *registeredListenerInvoker) before(o *registeredListenerInvoker) bool {
+	switch {
+	case i.priority != nil && o.priority != nil && *i.priority != *o.priority:
+		return *i.priority > *o.priority
+	case i.priority != nil && o.priority == nil:
+		return true
+	case i.priority == nil && o.priority != nil:
+		return false
+	default:
+		return i.seq < o.seq
+	}
+}
+
+func (a *airmidApplication) AddListenerInvoker(invoker ListenerInvoker, opts ...ListenerOption) func() {
+	registered := &registeredListenerInvoker{ListenerInvoker: invoker}
+	for _, opt := range opts {
+		opt(&registered.listenerOption)
+	}
+
+	a.listenerMu.Lock()
+	a.listenerSeq++
+	registered.seq = a.listenerSeq
+	invokers := append(make([]*registeredListenerInvoker, 0, len(a.listenerInvoker)+1), a.listenerInvoker...)
+	invokers = append(invokers, registered)
+	sort.SliceStable(invokers, func(i, j int) bool {
+		return invokers[i].before(invokers[j])
+	})
+	a.listenerInvoker = invokers
+	a.listenerCache = &sync.Map{}
+	a.listenerMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			a.listenerMu.Lock()
+			defer a.listenerMu.Unlock()
+
+			invokers := make([]*registeredListenerInvoker, 0, len(a.listenerInvoker))
+			for _, i := range a.listenerInvoker {
+				if i != registered {
+					invokers = append(invokers, i)
+				}
+			}
+			a.listenerInvoker = invokers
+			a.listenerCache = &sync.Map{}
+		})
+	}
+}
+
+// matchedListenerInvokers return the invokers which support the concrete type of event by priority, the
+// result is cached by the type, so the invokers are not matched by reflection on every publishing.
+func (a *airmidApplication) matchedListenerInvokers(event ApplicationEvent) []*registeredListenerInvoker {
+	a.listenerMu.RLock()
+	invokers, cache := a.listenerInvoker, a.listenerCache
+	a.listenerMu.RUnlock()
+
+	// The nil event's type is nil, which is a valid key too
+	typ := reflect.TypeOf(event)
+	if cache != nil {
+		if v, ok := cache.Load(typ); ok {
+			return v.([]*registeredListenerInvoker) //nolint:forcetypeassert
+		}
+	}
+
+	matched := make([]*registeredListenerInvoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if invoker.supportsEventType(typ) {
+			matched = append(matched, invoker)
+		}
+	}
+	if cache != nil {
+		cache.Store(typ, matched)
+	}
+	return matched
+}
+
+// validateEventErrorPolicy return error if the policy is not 'stop', 'continue' or 'collect'.
+func validateEventErrorPolicy(policy string) error {
+	switch policy {
+	case EventErrorPolicyStop, EventErrorPolicyContinue, EventErrorPolicyCollect:
+		return nil
+	default:
+		return xerrors.Errorf("Invalid event error policy '%v', it must be '%v', '%v' or '%v'",
+			policy, EventErrorPolicyStop, EventErrorPolicyContinue, EventErrorPolicyCollect)
 	}
 }
 
 func (a *airmidApplication) Submit(task func()) error {
 	return a.gpool.Submit(task)
 }
+
+func (a *airmidApplication) submitAsyncEvent(
+	ctx context.Context, listener any, name string, task func(context.Context)) error {
+	if a.eventExecutor != nil {
+		return a.eventExecutor.Submit(ctx, listener, name, task)
+	}
+
+	return a.Submit(func() {
+		//nolint:contextcheck
+		anvil.SafeRun(ctx, task)
+	})
+}
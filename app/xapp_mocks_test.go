@@ -54,6 +54,51 @@ func (mr *MockApplicationMockRecorder) AddBeanPostProcessor(beanPostProcessor an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBeanPostProcessor", reflect.TypeOf((*MockApplication)(nil).AddBeanPostProcessor), beanPostProcessor)
 }
 
+// AddListenerInvoker mocks base method.
+func (m *MockApplication) AddListenerInvoker(invoker ListenerInvoker, opts ...ListenerOption) func() {
+	m.ctrl.T.Helper()
+	varargs := []any{invoker}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddListenerInvoker", varargs...)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// AddListenerInvoker indicates an expected call of AddListenerInvoker.
+func (mr *MockApplicationMockRecorder) AddListenerInvoker(invoker any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{invoker}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddListenerInvoker", reflect.TypeOf((*MockApplication)(nil).AddListenerInvoker), varargs...)
+}
+
+// AddPropertySource mocks base method.
+func (m *MockApplication) AddPropertySource(source props.PropertySource, precedence props.Precedence) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddPropertySource", source, precedence)
+}
+
+// AddPropertySource indicates an expected call of AddPropertySource.
+func (mr *MockApplicationMockRecorder) AddPropertySource(source, precedence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPropertySource", reflect.TypeOf((*MockApplication)(nil).AddPropertySource), source, precedence)
+}
+
+// Args mocks base method.
+func (m *MockApplication) Args() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Args")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Args indicates an expected call of Args.
+func (mr *MockApplicationMockRecorder) Args() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Args", reflect.TypeOf((*MockApplication)(nil).Args))
+}
+
 // Destroy mocks base method.
 func (m *MockApplication) Destroy() {
 	m.ctrl.T.Helper()
@@ -116,6 +161,63 @@ func (mr *MockApplicationMockRecorder) GetBeanDefinition(beanName any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBeanDefinition", reflect.TypeOf((*MockApplication)(nil).GetBeanDefinition), beanName)
 }
 
+// Has mocks base method.
+func (m *MockApplication) Has(key string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Has", key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Has indicates an expected call of Has.
+func (mr *MockApplicationMockRecorder) Has(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Has", reflect.TypeOf((*MockApplication)(nil).Has), key)
+}
+
+// IsConsumed mocks base method.
+func (m *MockApplication) IsConsumed(key string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsConsumed", key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsConsumed indicates an expected call of IsConsumed.
+func (mr *MockApplicationMockRecorder) IsConsumed(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsConsumed", reflect.TypeOf((*MockApplication)(nil).IsConsumed), key)
+}
+
+// Keys mocks base method.
+func (m *MockApplication) Keys(prefix string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", prefix)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockApplicationMockRecorder) Keys(prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockApplication)(nil).Keys), prefix)
+}
+
+// Origin mocks base method.
+func (m *MockApplication) Origin(ctx context.Context, key string) (props.Origin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Origin", ctx, key)
+	ret0, _ := ret[0].(props.Origin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Origin indicates an expected call of Origin.
+func (mr *MockApplicationMockRecorder) Origin(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Origin", reflect.TypeOf((*MockApplication)(nil).Origin), ctx, key)
+}
+
 // PreInstantiateSingletons mocks base method.
 func (m *MockApplication) PreInstantiateSingletons(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -130,10 +232,26 @@ func (mr *MockApplicationMockRecorder) PreInstantiateSingletons(ctx any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreInstantiateSingletons", reflect.TypeOf((*MockApplication)(nil).PreInstantiateSingletons), ctx)
 }
 
+// PropertySources mocks base method.
+func (m *MockApplication) PropertySources() []props.PropertySource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PropertySources")
+	ret0, _ := ret[0].([]props.PropertySource)
+	return ret0
+}
+
+// PropertySources indicates an expected call of PropertySources.
+func (mr *MockApplicationMockRecorder) PropertySources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PropertySources", reflect.TypeOf((*MockApplication)(nil).PropertySources))
+}
+
 // PublishEvent mocks base method.
-func (m *MockApplication) PublishEvent(ctx context.Context, event ApplicationEvent) {
+func (m *MockApplication) PublishEvent(ctx context.Context, event ApplicationEvent) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "PublishEvent", ctx, event)
+	ret := m.ctrl.Call(m, "PublishEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // PublishEvent indicates an expected call of PublishEvent.
@@ -142,6 +260,20 @@ func (mr *MockApplicationMockRecorder) PublishEvent(ctx, event any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishEvent", reflect.TypeOf((*MockApplication)(nil).PublishEvent), ctx, event)
 }
 
+// RefreshProperties mocks base method.
+func (m *MockApplication) RefreshProperties(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshProperties", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshProperties indicates an expected call of RefreshProperties.
+func (mr *MockApplicationMockRecorder) RefreshProperties(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshProperties", reflect.TypeOf((*MockApplication)(nil).RefreshProperties), ctx)
+}
+
 // RegisterBeanDefinition mocks base method.
 func (m *MockApplication) RegisterBeanDefinition(beanName string, beanDefinition ioc.BeanDefinition) error {
 	m.ctrl.T.Helper()
@@ -184,6 +316,18 @@ func (mr *MockApplicationMockRecorder) RegisterSingleton(name, bean any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterSingleton", reflect.TypeOf((*MockApplication)(nil).RegisterSingleton), name, bean)
 }
 
+// Remove mocks base method.
+func (m *MockApplication) Remove(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Remove", key)
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockApplicationMockRecorder) Remove(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockApplication)(nil).Remove), key)
+}
+
 // RemoveBeanDefinition mocks base method.
 func (m *MockApplication) RemoveBeanDefinition(beanName string) error {
 	m.ctrl.T.Helper()
@@ -198,6 +342,18 @@ func (mr *MockApplicationMockRecorder) RemoveBeanDefinition(beanName any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBeanDefinition", reflect.TypeOf((*MockApplication)(nil).RemoveBeanDefinition), beanName)
 }
 
+// RemovePropertySource mocks base method.
+func (m *MockApplication) RemovePropertySource(name string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemovePropertySource", name)
+}
+
+// RemovePropertySource indicates an expected call of RemovePropertySource.
+func (mr *MockApplicationMockRecorder) RemovePropertySource(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePropertySource", reflect.TypeOf((*MockApplication)(nil).RemovePropertySource), name)
+}
+
 // ResolveBeanNames mocks base method.
 func (m *MockApplication) ResolveBeanNames(ctx context.Context, typ reflect.Type) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -246,6 +402,20 @@ func (mr *MockApplicationMockRecorder) Set(ctx, key, val any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockApplication)(nil).Set), ctx, key, val)
 }
 
+// SetAll mocks base method.
+func (m *MockApplication) SetAll(ctx context.Context, values map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAll", ctx, values)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAll indicates an expected call of SetAll.
+func (mr *MockApplicationMockRecorder) SetAll(ctx, values any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAll", reflect.TypeOf((*MockApplication)(nil).SetAll), ctx, values)
+}
+
 // Shutdown mocks base method.
 func (m *MockApplication) Shutdown() {
 	m.ctrl.T.Helper()
@@ -258,6 +428,20 @@ func (mr *MockApplicationMockRecorder) Shutdown() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockApplication)(nil).Shutdown))
 }
 
+// Snapshot mocks base method.
+func (m *MockApplication) Snapshot() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockApplicationMockRecorder) Snapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockApplication)(nil).Snapshot))
+}
+
 // Submit mocks base method.
 func (m *MockApplication) Submit(task func()) error {
 	m.ctrl.T.Helper()
@@ -272,6 +456,20 @@ func (mr *MockApplicationMockRecorder) Submit(task any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockApplication)(nil).Submit), task)
 }
 
+// UpdateProperties mocks base method.
+func (m *MockApplication) UpdateProperties(ctx context.Context, fn func(props.ConfigurableProperties) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProperties", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProperties indicates an expected call of UpdateProperties.
+func (mr *MockApplicationMockRecorder) UpdateProperties(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProperties", reflect.TypeOf((*MockApplication)(nil).UpdateProperties), ctx, fn)
+}
+
 // VisitBeanDefinition mocks base method.
 func (m *MockApplication) VisitBeanDefinition(visitor ioc.BeanDefinitionVisitor) {
 	m.ctrl.T.Helper()
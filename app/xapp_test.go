@@ -21,12 +21,16 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 
 	. "github.com/onsi/gomega"
 
+	"github.com/anyvoxel/airmid/anvil/xerrors"
 	"github.com/anyvoxel/airmid/ioc"
+	"github.com/anyvoxel/airmid/ioc/props"
 )
 
 type testApplicationEvent1 struct {
@@ -88,3 +92,100 @@ func TestPublishEvent(t *testing.T) {
 	g.Expect(l.ev2).To(Equal(2))
 	g.Expect(l.ev).To(Equal(2))
 }
+
+type testPropertiesChangedListener struct {
+	ioc.RefreshGuard
+
+	Port int `airmid:"value:${port:=80},refresh"`
+	keys [][]string
+}
+
+func (l *testPropertiesChangedListener) OnPropertiesChanged(_ context.Context, ev PropertiesChangedEvent) {
+	l.keys = append(l.keys, ev.Keys)
+}
+
+func TestUpdateProperties(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	app := NewApplication().(*airmidApplication)
+	app.AddBeanPostProcessor(&applicationListenerDetector{
+		app: app,
+		singletonNames: map[string]bool{
+			"l": true,
+		},
+	})
+
+	app.RegisterBeanDefinition("l", ioc.MustNewBeanDefinition(
+		reflect.TypeOf((*testPropertiesChangedListener)(nil)),
+	))
+	object, err := app.GetBean(ctx, "l")
+	g.Expect(err).ToNot(HaveOccurred())
+	l := object.(*testPropertiesChangedListener)
+
+	err = app.UpdateProperties(ctx, func(p props.ConfigurableProperties) error {
+		source := props.NewPropertySource("test")
+		if err := source.Set(ctx, "port", 8080); err != nil {
+			return err
+		}
+		p.AddPropertySource(source, props.PrecedenceConfigFile)
+		return p.Set(ctx, "host", "localhost")
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(l.Port).To(Equal(8080))
+	g.Expect(l.keys).To(Equal([][]string{{"host", "port"}}))
+
+	// Nothing is published if no key is changed
+	err = app.UpdateProperties(ctx, func(p props.ConfigurableProperties) error {
+		return p.Set(ctx, "host", "localhost")
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(l.keys).To(HaveLen(1))
+
+	err = app.UpdateProperties(ctx, func(p props.ConfigurableProperties) error {
+		return xerrors.Errorf("update failed")
+	})
+	g.Expect(err).To(MatchError("update failed"))
+
+	err = app.UpdateProperties(ctx, func(p props.ConfigurableProperties) error {
+		return p.Set(ctx, "port", "x")
+	})
+	g.Expect(err).To(MatchError(MatchRegexp("Cannot refresh field 'Port' of bean 'l'")))
+	g.Expect(l.Port).To(Equal(8080))
+	g.Expect(l.keys).To(Equal([][]string{{"host", "port"}, {"port"}}))
+}
+
+func TestUpdatePropertiesConcurrently(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	app := NewApplication().(*airmidApplication)
+
+	var mu sync.Mutex
+	keys := []string{}
+	Subscribe(app, func(_ context.Context, ev PropertiesChangedEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, ev.Keys...)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.Expect(app.UpdateProperties(ctx, func(p props.ConfigurableProperties) error {
+				return p.Set(ctx, fmt.Sprintf("key%d", i), i)
+			})).To(Succeed())
+		}()
+	}
+	wg.Wait()
+
+	// Each update only see the key changed by itself
+	g.Expect(keys).To(HaveLen(20))
+	g.Expect(keys).To(ConsistOf(func() []any {
+		ret := []any{}
+		for i := 0; i < 20; i++ {
+			ret = append(ret, fmt.Sprintf("key%d", i))
+		}
+		return ret
+	}()...))
+}
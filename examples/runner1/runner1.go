@@ -43,10 +43,13 @@ func (r *runner1) SetBeanFactory(beanFactory ioc.BeanFactory) {
 func (r *runner1) Run(ctx context.Context) {
 	slogctx.FromCtx(ctx).InfoContext(ctx, fmt.Sprintf("Runner1.Run called: attr='%v'\n", r.Attr))
 
-	r.publisher.PublishEvent(context.WithValue(ctx, k1, "v1"), &runner1RunEvent{
+	err := r.publisher.PublishEvent(context.WithValue(ctx, k1, "v1"), &runner1RunEvent{
 		ApplicationEvent: app.NewDefaultApplicationEvent(r),
 		desp:             "e1",
 	})
+	if err != nil {
+		slogctx.FromCtx(ctx).ErrorContext(ctx, fmt.Sprintf("Runner1 publish event failed: %v", err))
+	}
 
 	attr, _ := r.application.Get(ctx, "attr")
 	slogctx.FromCtx(ctx).InfoContext(ctx, fmt.Sprintf("Runner1 get properties: %s=%v", "attr", attr))
@@ -105,6 +105,12 @@ func NewBeanDefinition(typ reflect.Type, opts ...BeanDefinitionOption) (BeanDefi
 		fieldDescriptors = append(fieldDescriptors, *fd)
 	}
 
+	if fd, ok := unguardedRefreshField(typ, fieldDescriptors); ok {
+		return nil, xerrors.Errorf(
+			"Cannot build bean definition from '%s', the refreshable field '%s' requires it embed ioc.RefreshGuard",
+			typ.String(), fd.Name)
+	}
+
 	constructor, err := constructorBuilder.Build(typ, opt.construtorArguments)
 	if err != nil {
 		return nil, err
@@ -160,3 +166,18 @@ func (b *beanDefinitionHolder) Constructor() Constructor {
 func (b *beanDefinitionHolder) IsPrimary() bool {
 	return b.primary
 }
+
+// unguardedRefreshField return the refreshable field which isn't synchronized, the bean must implement
+// RefreshLocker if it has refreshable fields, except the PropertyHandle which always read current value.
+func unguardedRefreshField(typ reflect.Type, fds []FieldDescriptor) (FieldDescriptor, bool) {
+	if typ.Implements(refreshLockerType) {
+		return FieldDescriptor{}, false
+	}
+
+	for _, fd := range fds {
+		if isRefreshableField(fd) {
+			return fd, true
+		}
+	}
+	return FieldDescriptor{}, false
+}
@@ -60,15 +60,20 @@ type BeanFactory interface {
 	// Destroy will destroy the beans before bean destruction.
 	Destroy()
 
+	// RefreshProperties will re-inject the refreshable property fields of singletons with the
+	// current properties, the fields are updated only if all of them are resolved successfully.
+	// The fields of each bean are updated together under the write lock of its RefreshLocker.
+	RefreshProperties(ctx context.Context) error
+
 	BeanDefinitionRegistry
-	props.Properties
+	props.ConfigurableProperties
 }
 
 // NewBeanFactory return the BeanFactory impl.
 func NewBeanFactory() BeanFactory {
 	beanFactory := &beanFactoryImpl{
 		BeanDefinitionRegistry: NewBeanDefinitionRegistry(),
-		Properties:             props.NewProperties(),
+		ConfigurableProperties: props.NewProperties(),
 		singletonObjects:       make(map[string]reflect.Value),
 		allBeans:               make(map[string]any),
 		beansInCreating:        make(map[string]any),
@@ -83,7 +88,7 @@ func NewBeanFactory() BeanFactory {
 
 type beanFactoryImpl struct {
 	BeanDefinitionRegistry
-	props.Properties
+	props.ConfigurableProperties
 
 	// singletonObjects is the cache for singleton scope instance
 	singletonObjects map[string]reflect.Value
@@ -94,9 +99,18 @@ type beanFactoryImpl struct {
 
 	beanPostProcessorCompositor BeanPostProcessorCompositor
 
+	// refreshableFields is the property fields marked as refresh of singletons
+	refreshableFields []refreshableField
+
 	mu sync.RWMutex
 }
 
+type refreshableField struct {
+	beanName string
+	bean     reflect.Value
+	fd       FieldDescriptor
+}
+
 func (f *beanFactoryImpl) GetBean(ctx context.Context, name string) (any, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -141,10 +155,13 @@ func (f *beanFactoryImpl) getBeanLocked(ctx context.Context, name string) (any,
 	f.beansInCreating[name] = v.Interface()
 	defer delete(f.beansInCreating, name)
 
-	err = f.wireStruct(ctx, v, beanDefinition.FieldDescriptors())
+	err = f.wireStruct(ctx, name, v, beanDefinition.FieldDescriptors())
 	if err != nil {
 		return nil, err
 	}
+	if beanDefinition.Scope() == ScopeSingleton {
+		f.addRefreshableFields(name, v, beanDefinition.FieldDescriptors())
+	}
 
 	// TODO: optimize this. When A depends B and B depends A,
 	// if B's post process and initialization depends on A,
@@ -192,8 +209,9 @@ func (f *beanFactoryImpl) getBeanLocked(ctx context.Context, name string) (any,
 
 // wireStruct will inject the field value into bean.
 // 1. the bean must be pointer to struct.
-// 2. it will return err when any inject failed.
-func (f *beanFactoryImpl) wireStruct(ctx context.Context, bean reflect.Value, fds []FieldDescriptor) error {
+// 2. it will return err when any inject failed, the property error is wrapped with field and beanName.
+func (f *beanFactoryImpl) wireStruct(
+	ctx context.Context, beanName string, bean reflect.Value, fds []FieldDescriptor) error {
 	if len(fds) == 0 {
 		return nil
 	}
@@ -209,9 +227,14 @@ func (f *beanFactoryImpl) wireStruct(ctx context.Context, bean reflect.Value, fd
 			fn = f.getPropertyValue
 		case fd.Bean != nil:
 			fn = f.getBeanValue
+		case fd.Config != nil:
+			fn = f.getConfigValue
 		}
 
 		if err := fn(ctx, fd, propertyValues); err != nil {
+			if fd.Bean == nil && beanName != "" {
+				return xerrors.Wrapf(err, "Cannot wire field '%v' of bean '%v'", fd.Name, beanName)
+			}
 			return err
 		}
 	}
@@ -229,6 +252,177 @@ func (f *beanFactoryImpl) getPropertyValue(
 	if fd.Property.Default != nil {
 		opts = append(opts, props.WithDefault(*fd.Property.Default))
 	}
+
+	value, err := f.resolvePropertyValue(ctx, fd, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := f.validatePropertyValue(ctx, fd.Property, value); err != nil {
+		return xerrors.Wrapf(err, "Invalid value of key '%v'", fd.Property.Name)
+	}
+	propertyValues.AddValue(fd.FieldIndex, value)
+	return nil
+}
+
+func (f *beanFactoryImpl) resolvePropertyValue(
+	ctx context.Context, fd FieldDescriptor, opts []props.GetOption) (reflect.Value, error) {
+	handle, ok := newPropertyHandle(fd.Typ)
+	if !ok {
+		return f.resolvePropsValue(ctx, fd, fd.Property.Name, opts)
+	}
+
+	// The handle will read the current value of property by itself
+	handle.Interface().(props.PropertyHandle).AttachProperties(f.ConfigurableProperties, fd.Property.Name, opts...)
+	if fd.Typ.Kind() != reflect.Ptr {
+		handle = handle.Elem()
+	}
+	return handle, nil
+}
+
+func (f *beanFactoryImpl) validatePropertyValue(
+	ctx context.Context, pd *PropertyFieldDescriptor, value reflect.Value) error {
+	if len(pd.Rules) == 0 {
+		return nil
+	}
+
+	for _, rule := range pd.Rules {
+		if rule.Name != RequiredRule {
+			continue
+		}
+
+		_, err := f.Origin(ctx, pd.Name)
+		if xerrors.IsNotFound(err) {
+			return xerrors.Errorf("it is required")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return validateValue(ctx, value, pd.Rules, props.IsSecretKey(pd.Name) || f.isEncrypted(pd.Name))
+}
+
+// isEncrypted return true if the raw value of key is encrypted, so it must be redacted after decrypted.
+func (f *beanFactoryImpl) isEncrypted(key string) bool {
+	for _, s := range f.PropertySources() {
+		if v, ok := s.Lookup(key); ok {
+			return props.IsEncrypted(v)
+		}
+	}
+	return false
+}
+
+// newPropertyHandle return the new pointer value of typ if it implement props.PropertyHandle.
+func newPropertyHandle(typ reflect.Type) (reflect.Value, bool) {
+	handleType := reflect.TypeOf((*props.PropertyHandle)(nil)).Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if !reflect.PointerTo(typ).Implements(handleType) {
+		return reflect.Value{}, false
+	}
+	return reflect.New(typ), true
+}
+
+// isRefreshableField report whether the field should be re-injected on refresh, the PropertyHandle
+// is skipped because it always read the current value.
+func isRefreshableField(fd FieldDescriptor) bool {
+	if fd.Property == nil || !fd.Property.Refresh {
+		return false
+	}
+	_, ok := newPropertyHandle(fd.Typ)
+	return !ok
+}
+
+func (f *beanFactoryImpl) addRefreshableFields(beanName string, bean reflect.Value, fds []FieldDescriptor) {
+	for _, fd := range fds {
+		if !isRefreshableField(fd) {
+			continue
+		}
+
+		f.refreshableFields = append(f.refreshableFields, refreshableField{
+			beanName: beanName,
+			bean:     bean,
+			fd:       fd,
+		})
+	}
+}
+
+func (f *beanFactoryImpl) RefreshProperties(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// We resolve all of the values before update, so the fields won't be partially refreshed
+	values := make([]PropertyValues, 0, len(f.refreshableFields))
+	for _, rf := range f.refreshableFields {
+		propertyValues := NewPropertyValues()
+		if err := f.getPropertyValue(ctx, rf.fd, propertyValues); err != nil {
+			return xerrors.Wrapf(err, "Cannot refresh field '%v' of bean '%v'", rf.fd.Name, rf.beanName)
+		}
+		values = append(values, propertyValues)
+	}
+
+	// The fields of same bean are adjacent, they are updated under the lock of bean one by one,
+	// so the readers never see a half-refreshed bean.
+	for start := 0; start < len(f.refreshableFields); {
+		end := start + 1
+		for end < len(f.refreshableFields) && f.refreshableFields[end].beanName == f.refreshableFields[start].beanName {
+			end++
+		}
+		if err := f.refreshBean(ctx, f.refreshableFields[start:end], values[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// refreshBean set the refreshable fields of bean under the write lock of its RefreshLocker.
+func (*beanFactoryImpl) refreshBean(ctx context.Context, fields []refreshableField, values []PropertyValues) error {
+	mu := fields[0].bean.Interface().(RefreshLocker).RefreshLocker() //nolint:forcetypeassert
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i, rf := range fields {
+		err := values[i].SetProperty(ctx, rf.bean.Elem(), []FieldDescriptor{rf.fd})
+		if err != nil {
+			return err
+		}
+
+		slogctx.FromCtx(ctx).DebugContext(
+			ctx,
+			"refresh property field success",
+			slog.String("BeanName", rf.beanName),
+			slog.String("FieldName", rf.fd.Name),
+		)
+	}
+	return nil
+}
+
+func (f *beanFactoryImpl) getConfigValue(
+	ctx context.Context, fd FieldDescriptor, propertyValues PropertyValues) error {
+	if fd.Config == nil {
+		return nil
+	}
+
+	// The struct value will been bound with the property subtree under prefix
+	return f.getPropsValue(ctx, fd, fd.Config.Prefix, nil, propertyValues)
+}
+
+func (f *beanFactoryImpl) getPropsValue(
+	ctx context.Context, fd FieldDescriptor, key string, opts []props.GetOption, propertyValues PropertyValues) error {
+	propertyValue, err := f.resolvePropsValue(ctx, fd, key, opts)
+	if err != nil {
+		return err
+	}
+
+	propertyValues.AddValue(fd.FieldIndex, propertyValue)
+	return nil
+}
+
+func (f *beanFactoryImpl) resolvePropsValue(
+	ctx context.Context, fd FieldDescriptor, key string, opts []props.GetOption) (reflect.Value, error) {
 	typ := fd.Typ
 	if fd.Typ.Kind() != reflect.Ptr {
 		// If the typ is not pointer, we change th target type to pointer,
@@ -237,17 +431,16 @@ func (f *beanFactoryImpl) getPropertyValue(
 	}
 
 	opts = append(opts, props.WithType(typ))
-	value, err := f.Get(ctx, fd.Property.Name, opts...)
+	value, err := f.Get(ctx, key, opts...)
 	if err != nil {
-		return err
+		return reflect.Value{}, err
 	}
 
 	propertyValue := reflect.ValueOf(value)
 	if fd.Typ.Kind() != reflect.Ptr {
 		propertyValue = propertyValue.Elem()
 	}
-	propertyValues.AddValue(fd.FieldIndex, propertyValue)
-	return nil
+	return propertyValue, nil
 }
 
 func (f *beanFactoryImpl) getBeanValue(ctx context.Context, fd FieldDescriptor, propertyValues PropertyValues) error {
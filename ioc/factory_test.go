@@ -26,11 +26,13 @@ import (
 	"sort"
 	"sync"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
 	"github.com/anyvoxel/airmid/anvil/pointer"
 	"github.com/anyvoxel/airmid/anvil/xerrors"
+	"github.com/anyvoxel/airmid/ioc/props"
 )
 
 type testBeanOnlyPropertyField struct {
@@ -61,6 +63,43 @@ type testBeanMixField struct {
 	f1 int                        `airmid:"value:${props.f1}"`
 }
 
+type testPoolConfig struct {
+	Max  int    `prop:"max:=10"`
+	Mode string `prop:"mode:=lifo"`
+}
+
+type testDBConfig struct {
+	Host string          `prop:"host"`
+	Pool *testPoolConfig `prop:"pool"`
+}
+
+type testBeanConfigField struct {
+	db     testDBConfig  `airmid:"config:db"`
+	Backup *testDBConfig `airmid:"config:db.backup"`
+}
+
+type testBeanMapField struct {
+	labels map[string]string         `airmid:"value:${labels}"`
+	Groups map[string][]int          `airmid:"value:${groups:=}"`
+	limits map[string]map[string]int `airmid:"value:${limits:=}"`
+}
+
+type testBeanPlaceholderField struct {
+	url     string `airmid:"value:${url}"`
+	Port    int    `airmid:"value:${server.port:=${port:=80}}"`
+	Address string `airmid:"value:${address:=${host}:${server.port:=${port:=80}}}"`
+}
+
+type testBeanRefreshField struct {
+	RefreshGuard
+
+	Port    int                `airmid:"value:${port:=80},refresh"`
+	Host    string             `airmid:"value:${host}"`
+	timeout time.Duration      `airmid:"value:${timeout:=1s},refresh"`
+	Limit   *props.Value[int]  `airmid:"value:${limit:=10}"`
+	Tags    props.Value[[]int] `airmid:"value:${tags}"`
+}
+
 type testBeanNotImplement struct {
 	notImplementInterface testNotImplementInterface `airmid:"autowire:?"` //nolint
 }
@@ -181,6 +220,101 @@ func TestBeanFactoryGetBean(t *testing.T) {
 				f1: 200,
 			},
 		},
+		{
+			desp: "normal get bean with config field",
+			initFunc: func(g *WithT, tc *testCase, bf BeanFactory) {
+				err := bf.RegisterBeanDefinition("bean1", MustNewBeanDefinition(reflect.TypeOf((*testBeanConfigField)(nil))))
+				g.Expect(err).ToNot(HaveOccurred())
+
+				err = bf.Set(context.Background(), "db", map[string]any{
+					"host": "h1",
+					"pool": map[string]any{
+						"max": 20,
+					},
+					"backup": map[string]any{
+						"host": "h2",
+					},
+				})
+				g.Expect(err).ToNot(HaveOccurred())
+			},
+			beanName: "bean1",
+			err:      "",
+			expect: &testBeanConfigField{
+				db: testDBConfig{
+					Host: "h1",
+					Pool: &testPoolConfig{
+						Max:  20,
+						Mode: "lifo",
+					},
+				},
+				Backup: &testDBConfig{
+					Host: "h2",
+				},
+			},
+		},
+		{
+			desp: "get bean with config field convert failed",
+			initFunc: func(g *WithT, tc *testCase, bf BeanFactory) {
+				err := bf.RegisterBeanDefinition("bean1", MustNewBeanDefinition(reflect.TypeOf((*testBeanConfigField)(nil))))
+				g.Expect(err).ToNot(HaveOccurred())
+
+				err = bf.Set(context.Background(), "db.pool.max", "x")
+				g.Expect(err).ToNot(HaveOccurred())
+			},
+			beanName: "bean1",
+			err:      "Cannot bind property 'db.pool.max'",
+		},
+		{
+			desp: "normal get bean with map field",
+			initFunc: func(g *WithT, tc *testCase, bf BeanFactory) {
+				err := bf.RegisterBeanDefinition("bean1", MustNewBeanDefinition(reflect.TypeOf((*testBeanMapField)(nil))))
+				g.Expect(err).ToNot(HaveOccurred())
+
+				err = bf.Set(context.Background(), "labels", map[string]string{"k1": "v1", "k2": "v2"})
+				g.Expect(err).ToNot(HaveOccurred())
+				err = bf.Set(context.Background(), "groups", map[string][]int{"g1": {1, 2}})
+				g.Expect(err).ToNot(HaveOccurred())
+				err = bf.Set(context.Background(), "limits.cpu.max", 4)
+				g.Expect(err).ToNot(HaveOccurred())
+			},
+			beanName: "bean1",
+			err:      "",
+			expect: &testBeanMapField{
+				labels: map[string]string{"k1": "v1", "k2": "v2"},
+				Groups: map[string][]int{"g1": {1, 2}},
+				limits: map[string]map[string]int{"cpu": {"max": 4}},
+			},
+		},
+		{
+			desp: "get bean with map field not found",
+			initFunc: func(g *WithT, tc *testCase, bf BeanFactory) {
+				err := bf.RegisterBeanDefinition("bean1", MustNewBeanDefinition(reflect.TypeOf((*testBeanMapField)(nil))))
+				g.Expect(err).ToNot(HaveOccurred())
+			},
+			beanName: "bean1",
+			err:      "property map with key='labels' not found",
+		},
+		{
+			desp: "normal get bean with placeholder field",
+			initFunc: func(g *WithT, tc *testCase, bf BeanFactory) {
+				err := bf.RegisterBeanDefinition("bean1", MustNewBeanDefinition(reflect.TypeOf((*testBeanPlaceholderField)(nil))))
+				g.Expect(err).ToNot(HaveOccurred())
+
+				err = bf.Set(context.Background(), "host", "localhost")
+				g.Expect(err).ToNot(HaveOccurred())
+				err = bf.Set(context.Background(), "port", 8080)
+				g.Expect(err).ToNot(HaveOccurred())
+				err = bf.Set(context.Background(), "url", "http://${host}:${port}")
+				g.Expect(err).ToNot(HaveOccurred())
+			},
+			beanName: "bean1",
+			err:      "",
+			expect: &testBeanPlaceholderField{
+				url:     "http://localhost:8080",
+				Port:    8080,
+				Address: "localhost:8080",
+			},
+		},
 		{
 			desp: "lazymode bean with error",
 			initFunc: func(g *WithT, tc *testCase, bf BeanFactory) {
@@ -869,3 +1003,94 @@ func TestAutowireMultipleCandidatesWithNotImplementInterfaceLazyLoadBean(t *test
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(err.Error()).To(Equal("'2' candidates found for field 'test' with type ioc.testInterface"))
 }
+
+func TestBeanFactoryRefreshProperties(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	f := NewBeanFactory()
+	err := f.RegisterBeanDefinition("bean", MustNewBeanDefinition(reflect.TypeOf((*testBeanRefreshField)(nil))))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(f.Set(ctx, "host", "localhost")).To(Succeed())
+
+	obj, err := f.GetBean(ctx, "bean")
+	g.Expect(err).ToNot(HaveOccurred())
+	bean := obj.(*testBeanRefreshField)
+	g.Expect(bean.Port).To(Equal(80))
+	g.Expect(bean.timeout).To(Equal(time.Second))
+	g.Expect(bean.Limit.Get(ctx)).To(Equal(10))
+	_, err = bean.Tags.Get(ctx)
+	g.Expect(err).To(HaveOccurred())
+
+	g.Expect(f.Set(ctx, "port", 8080)).To(Succeed())
+	g.Expect(f.Set(ctx, "host", "127.0.0.1")).To(Succeed())
+	g.Expect(f.Set(ctx, "limit", 20)).To(Succeed())
+	g.Expect(f.Set(ctx, "tags", []int{1, 2})).To(Succeed())
+
+	// The handle always return the current value without refresh
+	g.Expect(bean.Limit.Get(ctx)).To(Equal(20))
+	g.Expect(bean.Tags.Get(ctx)).To(Equal([]int{1, 2}))
+	g.Expect(bean.Port).To(Equal(80))
+
+	err = f.RefreshProperties(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(bean.Port).To(Equal(8080))
+	g.Expect(bean.Host).To(Equal("localhost"))
+
+	// The fields won't be partially refreshed
+	g.Expect(f.Set(ctx, "port", 9090)).To(Succeed())
+	g.Expect(f.Set(ctx, "timeout", "x")).To(Succeed())
+	err = f.RefreshProperties(ctx)
+	g.Expect(err).To(MatchError(MatchRegexp("Cannot refresh field 'timeout' of bean 'bean'")))
+	g.Expect(bean.Port).To(Equal(8080))
+	g.Expect(bean.timeout).To(Equal(time.Second))
+}
+
+type testBeanUnguardedRefreshField struct {
+	Port int `airmid:"value:${port:=80},refresh"`
+}
+
+func TestNewBeanDefinitionRefreshField(t *testing.T) {
+	g := NewWithT(t)
+	_, err := NewBeanDefinition(reflect.TypeOf((*testBeanUnguardedRefreshField)(nil)))
+	g.Expect(err).To(MatchError(
+		"Cannot build bean definition from '*ioc.testBeanUnguardedRefreshField', " +
+			"the refreshable field 'Port' requires it embed ioc.RefreshGuard"))
+
+	_, err = NewBeanDefinition(reflect.TypeOf((*testBeanRefreshField)(nil)))
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func TestBeanFactoryRefreshPropertiesConcurrently(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	f := NewBeanFactory()
+	g.Expect(f.RegisterBeanDefinition("bean", MustNewBeanDefinition(reflect.TypeOf((*testBeanRefreshField)(nil))))).To(Succeed())
+	g.Expect(f.Set(ctx, "host", "localhost")).To(Succeed())
+	obj, err := f.GetBean(ctx, "bean")
+	g.Expect(err).ToNot(HaveOccurred())
+	bean := obj.(*testBeanRefreshField)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 100; i++ {
+			g.Expect(f.SetAll(ctx, map[string]any{"port": i, "timeout": time.Duration(i)})).To(Succeed())
+			g.Expect(f.RefreshProperties(ctx)).To(Succeed())
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			bean.RLock()
+			port, timeout := bean.Port, bean.timeout
+			bean.RUnlock()
+			if port != 80 {
+				// The fields of bean are refreshed together
+				g.Expect(time.Duration(port)).To(Equal(timeout))
+			}
+		}
+	}()
+	wg.Wait()
+	g.Expect(bean.Port).To(Equal(100))
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/anyvoxel/airmid/anvil/pointer"
 	"github.com/anyvoxel/airmid/anvil/xerrors"
+	"github.com/anyvoxel/airmid/ioc/props"
 )
 
 const (
@@ -38,33 +39,55 @@ const (
 	// AutowireContentPrefix is the prefix for bean field.
 	AutowireContentPrefix string = "autowire:"
 
+	// ConfigContentPrefix is the prefix for config field.
+	ConfigContentPrefix string = "config:"
+
 	// OptionalAutowireField is the constant value for optional bean field.
 	OptionalAutowireField string = "optional"
+
+	// RefreshPropertyField is the constant value for refreshable property field.
+	RefreshPropertyField string = "refresh"
 )
 
 // FieldDescriptor is the descriptor for struct field
 // The struct tag must format as:
-//  1. `airmid:"value:${name,default}"` for property field
+//  1. `airmid:"value:${name:=default},refresh,min=1"` for property field, the refresh and
+//     validation rules are optional, the rules can also be specified by the `validate` tag
 //  2. `airmid:"autowire:name,optional"` for bean field
+//  3. `airmid:"config:prefix"` for config field
 type FieldDescriptor struct {
 	FieldIndex int
 	Name       string
 	Typ        reflect.Type
 	Unexported bool
 
+	// Description is the field description for help.
+	// The field should be marked as desc:"text"
+	Description string
+
 	// Property is the field property descriptor.
-	// The field should be marked as value=${name:=default}
+	// The field should be marked as value=${name:=default},refresh
 	Property *PropertyFieldDescriptor
 
 	// Bean is the field bean descriptor.
 	// The field should be marked as autowire=name,optional
 	Bean *BeanFieldDescriptor
+
+	// Config is the field config descriptor.
+	// The field should be marked as config=prefix
+	Config *ConfigFieldDescriptor
 }
 
 // PropertyFieldDescriptor is the descriptor for property autowired value.
 type PropertyFieldDescriptor struct {
 	Name    string
 	Default *string
+
+	// Refresh indicate the field will be re-injected when the properties are refreshed.
+	Refresh bool
+
+	// Rules is the validation rules of value, which is checked when the field is injected.
+	Rules []ValidationRule
 }
 
 // BeanFieldDescriptor is the descriptor for bean autowired value.
@@ -73,6 +96,11 @@ type BeanFieldDescriptor struct {
 	Optional bool
 }
 
+// ConfigFieldDescriptor is the descriptor for property subtree autowired value.
+type ConfigFieldDescriptor struct {
+	Prefix string
+}
+
 // NewFieldDescriptor will return the descriptor from struct field
 // 1. If the field doesn't have airmid tag, return nil, nil
 // 2. If the field have airmid tag, return the field descriptor, otherwise return err.
@@ -87,6 +115,8 @@ func NewFieldDescriptor(field reflect.StructField, idx int) (*FieldDescriptor, e
 		Name:       field.Name,
 		Typ:        field.Type,
 		Unexported: false,
+
+		Description: field.Tag.Get(props.DescriptionTagName),
 	}
 	if field.PkgPath != "" {
 		fd.Unexported = true
@@ -105,8 +135,27 @@ func NewFieldDescriptor(field reflect.StructField, idx int) (*FieldDescriptor, e
 			return nil, err
 		}
 		fd.Bean = v
+	case strings.HasPrefix(tag, ConfigContentPrefix):
+		v, err := NewConfigFieldDescriptor(tag[len(ConfigContentPrefix):])
+		if err != nil {
+			return nil, err
+		}
+		fd.Config = v
 	default:
-		return nil, xerrors.Errorf("Invalid tag '%v', it must start with 'value:' or 'autowire:'", tag)
+		return nil, xerrors.Errorf("Invalid tag '%v', it must start with 'value:', 'autowire:' or 'config:'", tag)
+	}
+
+	if validate, ok := field.Tag.Lookup(ValidateTagName); ok {
+		if fd.Property == nil {
+			return nil, xerrors.Errorf(
+				"Invalid tag '%v' of field '%v', it can only be used with 'value:'", ValidateTagName, field.Name)
+		}
+
+		rules, err := NewValidationRules(validate)
+		if err != nil {
+			return nil, err
+		}
+		fd.Property.Rules = append(fd.Property.Rules, rules...)
 	}
 
 	return fd, nil
@@ -116,8 +165,59 @@ var (
 	valueRegex = regexp.MustCompile(`^\$\{(.*)\}$`)
 )
 
-// NewPropertyFieldDescriptor will return the descriptor from tag value.
+// splitValueOptions split the tag value into the placeholder and the options after it, e.g.
+// '${port:=80},refresh,min=1' is split into '${port:=80}' and 'refresh,min=1'. The placeholder
+// is ended at the brace which closes the leading '${', so the default can contain placeholder.
+func splitValueOptions(value string) (string, string, bool) {
+	if !strings.HasPrefix(value, "${") {
+		return value, "", false
+	}
+
+	depth := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				if i+1 < len(value) && value[i+1] == ',' {
+					return value[:i+1], value[i+2:], true
+				}
+				return value, "", false
+			}
+		}
+	}
+	return value, "", false
+}
+
+// NewPropertyFieldDescriptor will return the descriptor from tag value, the options after the
+// placeholder are split as same as NewValidationRules, e.g. "${name},refresh,regex='^[a-z]{2,3}$'".
 func NewPropertyFieldDescriptor(value string) (*PropertyFieldDescriptor, error) {
+	refresh := false
+	var rules []ValidationRule
+	if placeholder, options, ok := splitValueOptions(value); ok {
+		values, err := splitRules(options)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, option := range values {
+			if option == RefreshPropertyField {
+				refresh = true
+				continue
+			}
+
+			rule, err := NewValidationRule(option)
+			if err != nil {
+				return nil, xerrors.Wrapf(err, "Invalid value option '%v', it must be '%v' or validation rule",
+					option, RefreshPropertyField)
+			}
+			rules = append(rules, rule)
+		}
+		value = placeholder
+	}
+
 	res := valueRegex.FindAllStringSubmatch(value, -1)
 	if len(res) != 1 || len(res[0]) != 2 {
 		return nil, xerrors.Errorf("Invalid value '%v', it must format as ${x.y}", value)
@@ -130,7 +230,9 @@ func NewPropertyFieldDescriptor(value string) (*PropertyFieldDescriptor, error)
 
 	vv := strings.SplitN(value, ":=", 2)
 	fd := &PropertyFieldDescriptor{
-		Name: vv[0],
+		Name:    vv[0],
+		Refresh: refresh,
+		Rules:   rules,
 	}
 	if len(vv) > 1 {
 		fd.Default = pointer.StringPtr(vv[1])
@@ -158,3 +260,14 @@ func NewBeanFieldDescriptor(value string) (*BeanFieldDescriptor, error) {
 
 	return fd, nil
 }
+
+// NewConfigFieldDescriptor will return the descriptor from tag config.
+func NewConfigFieldDescriptor(value string) (*ConfigFieldDescriptor, error) {
+	if len(value) == 0 {
+		return nil, xerrors.Errorf("Required config content, it cann't be empty")
+	}
+
+	return &ConfigFieldDescriptor{
+		Prefix: value,
+	}, nil
+}
@@ -86,6 +86,44 @@ func TestNewFieldDescriptor(t *testing.T) {
 				},
 			},
 		},
+		{
+			desp: "with description",
+			field: reflect.StructField{
+				Name:    "f1",
+				PkgPath: "",
+				Tag:     reflect.StructTag(`airmid:"value:${v1}" desc:"the v1"`),
+			},
+			idx: 0,
+			expect: &FieldDescriptor{
+				FieldIndex:  0,
+				Name:        "f1",
+				Description: "the v1",
+				Property: &PropertyFieldDescriptor{
+					Name: "v1",
+				},
+			},
+		},
+		{
+			desp: "with validate tag",
+			field: reflect.StructField{
+				Name:    "f1",
+				PkgPath: "",
+				Tag:     reflect.StructTag(`airmid:"value:${v1},nonempty" validate:"required,oneof=json|text"`),
+			},
+			idx: 0,
+			expect: &FieldDescriptor{
+				FieldIndex: 0,
+				Name:       "f1",
+				Property: &PropertyFieldDescriptor{
+					Name: "v1",
+					Rules: []ValidationRule{
+						{Name: NonemptyRule},
+						{Name: RequiredRule},
+						{Name: OneofRule, Arg: "json|text"},
+					},
+				},
+			},
+		},
 		{
 			desp: "with bean field",
 			field: reflect.StructField{
@@ -104,6 +142,23 @@ func TestNewFieldDescriptor(t *testing.T) {
 				},
 			},
 		},
+		{
+			desp: "with config field",
+			field: reflect.StructField{
+				Name:    "f1",
+				PkgPath: "",
+				Tag:     reflect.StructTag(`airmid:"config:db"`),
+			},
+			idx: 2,
+			expect: &FieldDescriptor{
+				FieldIndex: 2,
+				Name:       "f1",
+				Unexported: false,
+				Config: &ConfigFieldDescriptor{
+					Prefix: "db",
+				},
+			},
+		},
 		{
 			desp: "non field",
 			field: reflect.StructField{
@@ -136,6 +191,17 @@ func TestNewFieldDescriptor(t *testing.T) {
 			expect: nil,
 			err:    "Invalid value '1'",
 		},
+		{
+			desp: "wrong config field",
+			field: reflect.StructField{
+				Name:    "f1",
+				PkgPath: "",
+				Tag:     reflect.StructTag(`airmid:"config:"`),
+			},
+			idx:    0,
+			expect: nil,
+			err:    "Required config content",
+		},
 		{
 			desp: "wrong bean field",
 			field: reflect.StructField{
@@ -147,6 +213,28 @@ func TestNewFieldDescriptor(t *testing.T) {
 			expect: nil,
 			err:    "Invalid autowire 'vv'",
 		},
+		{
+			desp: "validate tag on bean field",
+			field: reflect.StructField{
+				Name:    "f1",
+				PkgPath: "",
+				Tag:     reflect.StructTag(`airmid:"autowire:b1" validate:"required"`),
+			},
+			idx:    0,
+			expect: nil,
+			err:    "Invalid tag 'validate' of field 'f1', it can only be used with 'value:'",
+		},
+		{
+			desp: "wrong validate tag",
+			field: reflect.StructField{
+				Name:    "f1",
+				PkgPath: "",
+				Tag:     reflect.StructTag(`airmid:"value:${v1}" validate:"regex=("`),
+			},
+			idx:    0,
+			expect: nil,
+			err:    "Invalid rule 'regex=\\(', the argument must be regular expression",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desp, func(t *testing.T) {
@@ -253,12 +341,76 @@ func TestNewPropertyFieldDescriptor(t *testing.T) {
 			},
 			err: "",
 		},
+		{
+			desp:  "property with nested default",
+			value: "${b1:=${b2:=1}}",
+			expect: &PropertyFieldDescriptor{
+				Name:    "b1",
+				Default: pointer.StringPtr("${b2:=1}"),
+			},
+			err: "",
+		},
+		{
+			desp:  "refreshable property",
+			value: "${b1:=${b2},x},refresh",
+			expect: &PropertyFieldDescriptor{
+				Name:    "b1",
+				Default: pointer.StringPtr("${b2},x"),
+				Refresh: true,
+			},
+			err: "",
+		},
+		{
+			desp:  "property with rules",
+			value: "${port:=80},refresh,min=1,max=65535",
+			expect: &PropertyFieldDescriptor{
+				Name:    "port",
+				Default: pointer.StringPtr("80"),
+				Refresh: true,
+				Rules: []ValidationRule{
+					{Name: MinRule, Arg: "1"},
+					{Name: MaxRule, Arg: "65535"},
+				},
+			},
+			err: "",
+		},
+		{
+			desp:  "property with quoted regex",
+			value: "${code:={x}},regex='^[a-z]{2,3}$',refresh",
+			expect: &PropertyFieldDescriptor{
+				Name:    "code",
+				Default: pointer.StringPtr("{x}"),
+				Refresh: true,
+				Rules: []ValidationRule{
+					{Name: RegexRule, Arg: "^[a-z]{2,3}$"},
+				},
+			},
+			err: "",
+		},
+		{
+			desp:   "unclosed quote",
+			value:  "${code},regex='^a{2,3}$",
+			expect: nil,
+			err:    "Invalid rules 'regex='\\^a\\{2,3\\}\\$', the quote is not closed",
+		},
+		{
+			desp:   "invalid rule",
+			value:  "${port},min=x",
+			expect: nil,
+			err:    "Invalid value option 'min=x'.*Invalid rule 'min=x', the argument must be number or duration",
+		},
 		{
 			desp:   "format error",
 			value:  "b1",
 			expect: nil,
 			err:    "Invalid value 'b1'",
 		},
+		{
+			desp:   "invalid option",
+			value:  "${b1},lazy",
+			expect: nil,
+			err:    "Invalid value option 'lazy', it must be 'refresh'",
+		},
 		{
 			desp:   "empty value",
 			value:  "${}",
@@ -282,3 +434,43 @@ func TestNewPropertyFieldDescriptor(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfigFieldDescriptor(t *testing.T) {
+	type testCase struct {
+		desp   string
+		value  string
+		expect *ConfigFieldDescriptor
+		err    string
+	}
+	testCases := []testCase{
+		{
+			desp:  "normal prefix",
+			value: "db.primary",
+			expect: &ConfigFieldDescriptor{
+				Prefix: "db.primary",
+			},
+			err: "",
+		},
+		{
+			desp:   "empty content",
+			value:  "",
+			expect: nil,
+			err:    "Required config content",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fd, err := NewConfigFieldDescriptor(tc.value)
+			if tc.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).Should(MatchRegexp(tc.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(fd).To(Equal(tc.expect))
+		})
+	}
+}
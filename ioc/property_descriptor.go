@@ -0,0 +1,99 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ioc
+
+import (
+	"reflect"
+	"sort"
+)
+
+// PropertyDescriptor describe the property which is consumed by bean field, e.g. the
+// field marked as `airmid:"value:${name:=default}"` or `airmid:"config:prefix"`.
+type PropertyDescriptor struct {
+	// Key is the property key, or the prefix for config field
+	Key string
+
+	// Typ is the field type which the property is converted to
+	Typ reflect.Type
+
+	// Default is the default value of property, nil if not specified
+	Default *string
+
+	// Refresh indicate the field will be re-injected when the properties are refreshed
+	Refresh bool
+
+	// Config indicate the key is the prefix of property subtree
+	Config bool
+
+	// Description is the description of field, see FieldDescriptor.Description
+	Description string
+
+	// BeanName & FieldName is the bean field which consume the property
+	BeanName  string
+	FieldName string
+}
+
+// PropertyDescriptors return the properties consumed by all bean definitions in registry,
+// which is ordered by key, bean name and field name.
+func PropertyDescriptors(registry BeanDefinitionRegistry) []PropertyDescriptor {
+	ret := []PropertyDescriptor{}
+	registry.VisitBeanDefinition(FuncVisitor{
+		VisitFunc: func(beanName string, beanDefinition BeanDefinition) {
+			if beanDefinition == nil {
+				return
+			}
+
+			for _, fd := range beanDefinition.FieldDescriptors() {
+				switch {
+				case fd.Property != nil:
+					ret = append(ret, PropertyDescriptor{
+						Key:         fd.Property.Name,
+						Typ:         fd.Typ,
+						Default:     fd.Property.Default,
+						Refresh:     fd.Property.Refresh,
+						Description: fd.Description,
+						BeanName:    beanName,
+						FieldName:   fd.Name,
+					})
+				case fd.Config != nil:
+					ret = append(ret, PropertyDescriptor{
+						Key:         fd.Config.Prefix,
+						Typ:         fd.Typ,
+						Config:      true,
+						Description: fd.Description,
+						BeanName:    beanName,
+						FieldName:   fd.Name,
+					})
+				}
+			}
+		},
+	})
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Key != ret[j].Key {
+			return ret[i].Key < ret[j].Key
+		}
+		if ret[i].BeanName != ret[j].BeanName {
+			return ret[i].BeanName < ret[j].BeanName
+		}
+		return ret[i].FieldName < ret[j].FieldName
+	})
+	return ret
+}
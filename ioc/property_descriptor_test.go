@@ -0,0 +1,86 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ioc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/anyvoxel/airmid/anvil/pointer"
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+type testPropertyDescriptorConfig struct {
+	Host string
+}
+
+type testPropertyDescriptorBean1 struct {
+	port    int                           `airmid:"value:${server.port:=8080}"`
+	timeout *props.Value[time.Duration]   `airmid:"value:${server.timeout},refresh"`
+	db      *testPropertyDescriptorConfig `airmid:"config:db"`
+	other   *testPropertyDescriptorBean2  `airmid:"autowire:?"`
+}
+
+type testPropertyDescriptorBean2 struct {
+	port string `airmid:"value:${server.port}"`
+}
+
+func TestPropertyDescriptors(t *testing.T) {
+	g := NewWithT(t)
+	r := NewBeanDefinitionRegistry()
+	g.Expect(r.RegisterBeanDefinition("b1", MustNewBeanDefinition(
+		reflect.TypeOf((*testPropertyDescriptorBean1)(nil))))).To(Succeed())
+	g.Expect(r.RegisterBeanDefinition("b2", MustNewBeanDefinition(
+		reflect.TypeOf((*testPropertyDescriptorBean2)(nil))))).To(Succeed())
+	g.Expect(r.RegisterBeanDefinition("b3", nil)).To(Succeed())
+
+	g.Expect(PropertyDescriptors(r)).To(Equal([]PropertyDescriptor{
+		{
+			Key:       "db",
+			Typ:       reflect.TypeOf((*testPropertyDescriptorConfig)(nil)),
+			Config:    true,
+			BeanName:  "b1",
+			FieldName: "db",
+		},
+		{
+			Key:       "server.port",
+			Typ:       reflect.TypeOf(0),
+			Default:   pointer.StringPtr("8080"),
+			BeanName:  "b1",
+			FieldName: "port",
+		},
+		{
+			Key:       "server.port",
+			Typ:       reflect.TypeOf(""),
+			BeanName:  "b2",
+			FieldName: "port",
+		},
+		{
+			Key:       "server.timeout",
+			Typ:       reflect.TypeOf((*props.Value[time.Duration])(nil)),
+			Refresh:   true,
+			BeanName:  "b1",
+			FieldName: "timeout",
+		},
+	}))
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+)
+
+// GetAs return the value of key in p as type T, the first def is used as the default value if
+// the key is not found, e.g. GetAs[time.Duration](ctx, p, "http.timeout", "5s").
+func GetAs[T any](ctx context.Context, p Properties, key string, def ...string) (T, error) {
+	var ret T
+	opts := []GetOption{WithTarget(&ret)}
+	if len(def) > 0 {
+		opts = append(opts, WithDefault(def[0]))
+	}
+
+	if _, err := p.Get(ctx, key, opts...); err != nil {
+		var zero T
+		return zero, err
+	}
+	return ret, nil
+}
+
+// MustGetAs is like GetAs but panics if the value cannot be got.
+func MustGetAs[T any](ctx context.Context, p Properties, key string, def ...string) T {
+	ret, err := GetAs[T](ctx, p, key, def...)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
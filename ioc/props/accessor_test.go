@@ -0,0 +1,101 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGetAs(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	p := NewProperties()
+	g.Expect(p.Set(ctx, "http", map[string]any{
+		"timeout": "3s",
+		"hosts":   []string{"a", "b"},
+		"port":    "${http.default-port:=80}",
+	})).To(Succeed())
+
+	g.Expect(GetAs[time.Duration](ctx, p, "http.timeout")).To(Equal(3 * time.Second))
+	g.Expect(GetAs[[]string](ctx, p, "http.hosts")).To(Equal([]string{"a", "b"}))
+	g.Expect(GetAs[int](ctx, p, "http.port")).To(Equal(80))
+	g.Expect(GetAs[int](ctx, p, "http.retries", "3")).To(Equal(3))
+
+	_, err := GetAs[int](ctx, p, "http.retries")
+	g.Expect(err).To(MatchError(ContainSubstring("not found")))
+	v, err := GetAs[int](ctx, p, "http.timeout")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(v).To(Equal(0))
+
+	g.Expect(MustGetAs[bool](ctx, p, "http.tls", "true")).To(BeTrue())
+	g.Expect(func() {
+		MustGetAs[bool](ctx, p, "http.tls")
+	}).To(Panic())
+}
+
+func TestPropertiesIntrospection(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	p := NewProperties()
+	config := NewPropertySource("config")
+	g.Expect(config.Set(ctx, "db", map[string]any{
+		"host":     "h1",
+		"replicas": []string{"r1", "r2"},
+		"dsn":      "${db.host}:3306",
+	})).To(Succeed())
+	g.Expect(config.Set(ctx, "dbx", "x")).To(Succeed())
+	p.AddPropertySource(config, PrecedenceConfigFile)
+	g.Expect(p.Set(ctx, "db.host", "h2")).To(Succeed())
+
+	g.Expect(p.Keys("db")).To(Equal([]string{"db.dsn", "db.host", "db.replicas[0]", "db.replicas[1]"}))
+	g.Expect(p.Keys("DB.Replicas")).To(Equal([]string{"db.replicas[0]", "db.replicas[1]"}))
+	g.Expect(p.Keys("")).To(Equal([]string{"db.dsn", "db.host", "db.replicas[0]", "db.replicas[1]", "dbx"}))
+	g.Expect(p.Has("db")).To(BeTrue())
+	g.Expect(p.Has("db.replicas")).To(BeTrue())
+	g.Expect(p.Has("db.port")).To(BeFalse())
+	g.Expect(p.Snapshot()).To(Equal(map[string]string{
+		"db.dsn":         "h2:3306",
+		"db.host":        "h2",
+		"db.replicas[0]": "r1",
+		"db.replicas[1]": "r2",
+		"dbx":            "x",
+	}))
+	g.Expect(config.Snapshot()).To(HaveKeyWithValue("db.dsn", "h1:3306"))
+
+	// Only the runtime value is removed, the value in config source is visible again
+	p.Remove("DB.Host")
+	g.Expect(p.Get(ctx, "db.host")).To(Equal("h1"))
+	g.Expect(p.Snapshot()).To(HaveKeyWithValue("db.dsn", "h1:3306"))
+	p.Remove("db")
+	g.Expect(p.Keys("")).To(Equal([]string{"db.dsn", "db.host", "db.replicas[0]", "db.replicas[1]", "dbx"}))
+
+	// The key in source is removed by the source itself
+	config.Remove("db.replicas")
+	g.Expect(p.Has("db.replicas")).To(BeFalse())
+	config.Remove("db")
+	g.Expect(p.Keys("")).To(Equal([]string{"dbx"}))
+	g.Expect(config.Location("db.host")).To(BeEmpty())
+	_, err := p.Get(ctx, "db.host")
+	g.Expect(err).To(HaveOccurred())
+}
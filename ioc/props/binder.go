@@ -0,0 +1,275 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/anyvoxel/airmid/anvil/conv"
+	"github.com/anyvoxel/airmid/anvil/pointer"
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+const (
+	// PropTagName is the tag name to specify the property key of struct field when binding.
+	// The tag should be formatted as `prop:"name"` or `prop:"name:=default"`, the field
+	// will be skipped if the tag is `prop:"-"`.
+	PropTagName string = "prop"
+)
+
+// Bind will bind the properties under prefix onto target, the target must be a pointer to struct.
+// The nested keys (e.g. 'db.host', 'db.pool.max', 'db.replicas[0].host') will be mapped onto the
+// nested structs, slices and maps of target, the field which key is not found will be kept unchanged.
+func Bind(ctx context.Context, p Properties, prefix string, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return xerrors.Errorf("Bind: target '%T' must be a non-nil pointer to struct", target)
+	}
+
+	_, err := p.Get(ctx, prefix, WithTarget(target))
+	return err
+}
+
+// bindValue will bind the properties under key onto v, it return true if any property is found.
+//
+//nolint:exhaustive
+func (p propertyReader) bindValue(ctx context.Context, key string, v reflect.Value, def *string) (bool, error) {
+	kind := v.Kind()
+	if conv.HasConverter(v.Type()) {
+		// The registered type (e.g. time.Time, *url.URL) is converted from the value of key
+		kind = reflect.Invalid
+	}
+
+	switch kind {
+	case reflect.Struct:
+		return p.bindStruct(ctx, key, v)
+	case reflect.Ptr:
+		return p.bindPtr(ctx, key, v, def)
+	case reflect.Map:
+		return p.bindMap(ctx, key, v, def)
+	case reflect.Slice:
+		if isBindableType(v.Type()) {
+			return p.bindSlice(ctx, key, v)
+		}
+	}
+
+	opts := []GetOption{WithTarget(v.Addr())}
+	if def != nil {
+		opts = append(opts, WithDefault(*def))
+	}
+	_, err := p.Get(ctx, key, opts...)
+	if err != nil {
+		if xerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, xerrors.Wrapf(err, "Cannot bind property '%v'", key)
+	}
+
+	// The default value doesn't mean the property is found
+	return p.hasKey(key), nil
+}
+
+func (p propertyReader) bindStruct(ctx context.Context, prefix string, v reflect.Value) (bool, error) {
+	found := false
+	typ := v.Type()
+	for idx := 0; idx < typ.NumField(); idx++ {
+		field := typ.Field(idx)
+		name, def, ok := fieldPropName(field)
+		if !ok {
+			continue
+		}
+
+		key := joinPropKey(prefix, name)
+		if field.Anonymous && name == "" {
+			// The embedded struct without prop tag will be squashed into parent
+			key = prefix
+		}
+
+		ok, err := p.bindValue(ctx, key, setableField(v.Field(idx)), def)
+		if err != nil {
+			return false, err
+		}
+		found = found || ok
+	}
+
+	return found, nil
+}
+
+func (p propertyReader) bindPtr(ctx context.Context, key string, v reflect.Value, def *string) (bool, error) {
+	elem := reflect.New(v.Type().Elem())
+	if !v.IsNil() {
+		elem.Elem().Set(v.Elem())
+	}
+
+	found, err := p.bindValue(ctx, key, elem.Elem(), def)
+	if err != nil || !found {
+		return false, err
+	}
+
+	v.Set(elem)
+	return true, nil
+}
+
+// bindMap will bind the child keys under prefix onto map, e.g. 'labels.k1', 'labels.k2[0]'. If there is
+// no child keys, the value of prefix (e.g. 'labels[0]=k1=v1') will be converted with 'key=value' format.
+func (p propertyReader) bindMap(ctx context.Context, prefix string, v reflect.Value, def *string) (bool, error) {
+	names := p.childNames(prefix)
+	if len(names) == 0 {
+		return p.bindMapEntries(ctx, prefix, v, def)
+	}
+
+	typ := v.Type()
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(typ, len(names)))
+	}
+
+	for _, name := range names {
+		mk, err := conv.ConvertTo(ctx, typ.Key(), []string{name})
+		if err != nil {
+			return false, xerrors.Wrapf(err, "Cannot convert map's key '%v' to %v", name, typ.Key())
+		}
+
+		mv := reflect.New(typ.Elem()).Elem()
+		if _, err = p.bindValue(ctx, joinPropKey(prefix, name), mv, nil); err != nil {
+			return false, err
+		}
+		v.SetMapIndex(reflect.ValueOf(mk).Convert(typ.Key()), mv)
+	}
+	return true, nil
+}
+
+func (p propertyReader) bindMapEntries(ctx context.Context, key string, v reflect.Value, def *string) (bool, error) {
+	vstrs, err := p.getSlice(key, def)
+	if err != nil {
+		if xerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, xerrors.Wrapf(err, "Cannot bind property '%v'", key)
+	}
+
+	cv, err := conv.ConvertTo(ctx, v.Type(), vstrs)
+	if err != nil {
+		return false, xerrors.Wrapf(err, "Cannot bind property '%v'", key)
+	}
+
+	v.Set(reflect.ValueOf(cv))
+	return p.hasKey(key), nil
+}
+
+func (p propertyReader) bindSlice(ctx context.Context, prefix string, v reflect.Value) (bool, error) {
+	indexes, err := p.childIndexes(prefix)
+	if err != nil || len(indexes) == 0 {
+		return false, err
+	}
+
+	ret := reflect.MakeSlice(v.Type(), 0, len(indexes))
+	for _, i := range indexes {
+		ev := reflect.New(v.Type().Elem()).Elem()
+		if _, err = p.bindValue(ctx, prefix+"["+strconv.FormatInt(i, 10)+"]", ev, nil); err != nil {
+			return false, err
+		}
+		ret = reflect.Append(ret, ev)
+	}
+
+	v.Set(ret)
+	return true, nil
+}
+
+func (p propertiesImpl) hasKey(key string) bool {
+	return newKeyIndex(p).hasKey(key)
+}
+
+func (p propertiesImpl) childNames(prefix string) []string {
+	return newKeyIndex(p).childNames(prefix)
+}
+
+func (p propertiesImpl) childIndexes(prefix string) ([]int64, error) {
+	return newKeyIndex(p).childIndexes(prefix)
+}
+
+// isBindableType return true if the typ cannot be converted from string directly,
+// and it must be bound by the nested keys.
+//
+//nolint:exhaustive
+func isBindableType(typ reflect.Type) bool {
+	// The registered type (e.g. time.Time) is converted from string even if it is struct
+	if conv.HasConverter(typ) {
+		return false
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct, reflect.Map:
+		return true
+	case reflect.Ptr:
+		return isBindableType(typ.Elem())
+	case reflect.Slice:
+		// The nested slice (e.g. [][]int) is bound by the indexed keys, e.g. 'key[0][1]'
+		elem := typ.Elem()
+		nested := (elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array) && !conv.HasConverter(elem)
+		return nested || isBindableType(elem)
+	}
+	return false
+}
+
+// fieldPropName return the property name & default value of struct field, the name
+// is the `prop` tag or the field name with first letter lowercased.
+func fieldPropName(field reflect.StructField) (string, *string, bool) {
+	tag, ok := field.Tag.Lookup(PropTagName)
+	if tag == "-" {
+		return "", nil, false
+	}
+
+	if !ok || tag == "" {
+		if field.Anonymous {
+			return "", nil, true
+		}
+
+		r, n := utf8.DecodeRuneInString(field.Name)
+		return string(unicode.ToLower(r)) + field.Name[n:], nil, true
+	}
+
+	vv := strings.SplitN(tag, ":=", 2)
+	if len(vv) > 1 {
+		return vv[0], pointer.StringPtr(vv[1]), true
+	}
+	return vv[0], nil, true
+}
+
+func joinPropKey(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// setableField return the setable value of struct field, the unexported field cannot be set directly.
+func setableField(fv reflect.Value) reflect.Value {
+	if fv.CanSet() {
+		return fv
+	}
+
+	return reflect.NewAt(fv.Type(), unsafe.Pointer(fv.UnsafeAddr())).Elem()
+}
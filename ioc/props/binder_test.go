@@ -0,0 +1,332 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/anyvoxel/airmid/anvil/conv"
+)
+
+type testPoolConfig struct {
+	Max     int           `prop:"max:=10"`
+	Timeout time.Duration `prop:"timeout:=1s"`
+}
+
+type testReplicaConfig struct {
+	Host string
+	Port int `prop:"port:=3306"`
+}
+
+type testBaseConfig struct {
+	Name string `prop:"name"`
+}
+
+type testDBConfig struct {
+	testBaseConfig
+
+	Host     string              `prop:"host:=localhost"`
+	Pool     testPoolConfig      `prop:"pool"`
+	Replicas []testReplicaConfig `prop:"replicas"`
+	Labels   map[string]string   `prop:"labels"`
+	Backup   *testReplicaConfig  `prop:"backup"`
+	Tags     []string            `prop:"tags:=a,b"`
+	Ignored  string              `prop:"-"`
+
+	user string
+}
+
+func TestBind(t *testing.T) {
+	type testCase struct {
+		desp   string
+		p      propertiesImpl
+		prefix string
+		target any
+
+		err    string
+		expect any
+	}
+	testCases := []testCase{
+		{
+			desp: "bind nested keys",
+			p: propertiesImpl(map[string]string{
+				"db.name":             "n1",
+				"db.host":             "h1",
+				"db.pool.max":         "20",
+				"db.replicas[0].host": "r0",
+				"db.replicas[1].host": "r1",
+				"db.replicas[1].port": "3307",
+				"db.labels.k1":        "v1",
+				"db.labels.k2":        "v2",
+				"db.backup.host":      "b1",
+				"db.tags[0]":          "t1",
+				"db.ignored":          "x",
+				"db.user":             "u1",
+			}),
+			prefix: "db",
+			target: &testDBConfig{},
+			err:    "",
+			expect: &testDBConfig{
+				testBaseConfig: testBaseConfig{
+					Name: "n1",
+				},
+				Host: "h1",
+				Pool: testPoolConfig{
+					Max:     20,
+					Timeout: time.Second,
+				},
+				Replicas: []testReplicaConfig{
+					{Host: "r0", Port: 3306},
+					{Host: "r1", Port: 3307},
+				},
+				Labels: map[string]string{
+					"k1": "v1",
+					"k2": "v2",
+				},
+				Backup: &testReplicaConfig{
+					Host: "b1",
+					Port: 3306,
+				},
+				Tags: []string{"t1"},
+				user: "u1",
+			},
+		},
+		{
+			desp:   "bind with defaults",
+			p:      propertiesImpl(map[string]string{}),
+			prefix: "db",
+			target: &testDBConfig{
+				user: "u0",
+			},
+			err: "",
+			expect: &testDBConfig{
+				Host: "localhost",
+				Pool: testPoolConfig{
+					Max:     10,
+					Timeout: time.Second,
+				},
+				Tags: []string{"a", "b"},
+				user: "u0",
+			},
+		},
+		{
+			desp: "bind with empty prefix",
+			p: propertiesImpl(map[string]string{
+				"max":     "1",
+				"timeout": "2s",
+			}),
+			prefix: "",
+			target: &testPoolConfig{},
+			err:    "",
+			expect: &testPoolConfig{
+				Max:     1,
+				Timeout: 2 * time.Second,
+			},
+		},
+		{
+			desp: "bind convert failed",
+			p: propertiesImpl(map[string]string{
+				"db.pool.max": "x",
+			}),
+			prefix: "db",
+			target: &testDBConfig{},
+			err:    "Cannot bind property 'db.pool.max'",
+		},
+		{
+			desp: "bind index parse failed",
+			p: propertiesImpl(map[string]string{
+				"db.replicas[a].host": "r0",
+			}),
+			prefix: "db",
+			target: &testDBConfig{},
+			err:    `Invalid index of key 'db.replicas\[a\].host'`,
+		},
+		{
+			desp:   "bind non struct pointer",
+			p:      propertiesImpl(map[string]string{}),
+			prefix: "db",
+			target: testDBConfig{},
+			err:    "target 'props.testDBConfig' must be a non-nil pointer to struct",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := Bind(context.Background(), tc.p, tc.prefix, tc.target)
+			if tc.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(MatchRegexp(tc.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(tc.target).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestGetStruct(t *testing.T) {
+	g := NewWithT(t)
+
+	p := propertiesImpl(map[string]string{
+		"db.replicas[0].host": "r0",
+		"db.replicas[1].host": "r1",
+	})
+	actual, err := p.Get(context.Background(), "db.replicas", WithType(reflect.TypeOf([]testReplicaConfig{})))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(actual).To(Equal([]testReplicaConfig{
+		{Host: "r0", Port: 3306},
+		{Host: "r1", Port: 3306},
+	}))
+
+	actual, err = p.Get(context.Background(), "db.replicas[1]", WithType(reflect.TypeOf(&testReplicaConfig{})))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(actual).To(Equal(&testReplicaConfig{Host: "r1", Port: 3306}))
+}
+
+type testServerConfig struct {
+	Since   time.Time      `prop:"since:=2025-01-02"`
+	Proxy   *url.URL       `prop:"proxy"`
+	Allow   []net.IP       `prop:"allow"`
+	MaxBody conv.ByteSize  `prop:"maxBody:=1MiB"`
+	Level   slog.Level     `prop:"level:=INFO"`
+	Subnet  netip.Prefix   `prop:"subnet"`
+	Pattern *regexp.Regexp `prop:"pattern:=^/api"`
+}
+
+func TestGetRegisteredType(t *testing.T) {
+	g := NewWithT(t)
+
+	p := NewProperties()
+	g.Expect(p.Set(context.Background(), "server", map[string]any{
+		"proxy":  "http://proxy:8080",
+		"allow":  []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")},
+		"level":  slog.LevelWarn,
+		"subnet": netip.MustParsePrefix("10.0.0.0/8"),
+	})).To(Succeed())
+	g.Expect(p.Set(context.Background(), "server.ip", net.ParseIP("10.0.0.2"))).To(Succeed())
+
+	var cfg testServerConfig
+	_, err := p.Get(context.Background(), "server", WithTarget(&cfg))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cfg).To(Equal(testServerConfig{
+		Since:   time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		Proxy:   &url.URL{Scheme: "http", Host: "proxy:8080"},
+		Allow:   []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")},
+		MaxBody: conv.MiB,
+		Level:   slog.LevelWarn,
+		Subnet:  netip.MustParsePrefix("10.0.0.0/8"),
+		Pattern: regexp.MustCompile("^/api"),
+	}))
+
+	v, err := p.Get(context.Background(), "server.ip")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(v).To(Equal("10.0.0.2"))
+	v, err = p.Get(context.Background(), "server.ip", WithType(reflect.TypeOf(net.IP{})))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(v).To(Equal(net.ParseIP("10.0.0.2")))
+}
+
+func TestChildNames(t *testing.T) {
+	g := NewWithT(t)
+
+	p := propertiesImpl(map[string]string{
+		"db.host":             "h",
+		"db.pool.max":         "1",
+		"db.replicas[0].host": "r0",
+		"dbx":                 "x",
+		"port":                "80",
+	})
+	g.Expect(p.childNames("db")).To(Equal([]string{"host", "pool", "replicas"}))
+	g.Expect(p.childNames("")).To(Equal([]string{"db", "dbx", "port"}))
+	g.Expect(p.childNames("x")).To(BeEmpty())
+}
+
+func TestFieldPropName(t *testing.T) {
+	type testStruct struct {
+		testBaseConfig
+
+		MaxConns int
+		Host     string `prop:"host.name:=localhost"`
+		Ignored  string `prop:"-"`
+	}
+
+	type testCase struct {
+		desp  string
+		field string
+
+		name string
+		def  *string
+		ok   bool
+	}
+	testCases := []testCase{
+		{
+			desp:  "embedded struct",
+			field: "testBaseConfig",
+			name:  "",
+			ok:    true,
+		},
+		{
+			desp:  "without tag",
+			field: "MaxConns",
+			name:  "maxConns",
+			ok:    true,
+		},
+		{
+			desp:  "with tag and default",
+			field: "Host",
+			name:  "host.name",
+			def: func() *string {
+				v := "localhost"
+				return &v
+			}(),
+			ok: true,
+		},
+		{
+			desp:  "ignored",
+			field: "Ignored",
+			ok:    false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+
+			field, ok := reflect.TypeOf(testStruct{}).FieldByName(tc.field)
+			g.Expect(ok).To(BeTrue())
+
+			name, def, ok := fieldPropName(field)
+			g.Expect(ok).To(Equal(tc.ok))
+			g.Expect(name).To(Equal(tc.name))
+			g.Expect(def).To(Equal(tc.def))
+		})
+	}
+}
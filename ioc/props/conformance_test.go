@@ -0,0 +1,141 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/anyvoxel/airmid/anvil/conv"
+)
+
+type testMode string
+
+type testLevel int
+
+// TestSetGetConformance verify the value set by Properties.Set can be read back by Properties.Get
+// with the same type, and the flattened keys are stable.
+func TestSetGetConformance(t *testing.T) {
+	one, two := 1, 2
+	type testCase struct {
+		desp  string
+		value any
+		keys  map[string]string
+	}
+	testCases := []testCase{
+		{
+			desp:  "named string",
+			value: testMode("a"),
+			keys:  map[string]string{"k": "a"},
+		},
+		{
+			desp:  "named int",
+			value: testLevel(3),
+			keys:  map[string]string{"k": "3"},
+		},
+		{
+			desp:  "pointer",
+			value: &one,
+			keys:  map[string]string{"k": "1"},
+		},
+		{
+			desp:  "slice of named",
+			value: []testMode{"a", "b"},
+			keys:  map[string]string{"k[0]": "a", "k[1]": "b"},
+		},
+		{
+			desp:  "slice of pointer",
+			value: []*int{&one, &two},
+			keys:  map[string]string{"k[0]": "1", "k[1]": "2"},
+		},
+		{
+			desp:  "empty slice",
+			value: []string{},
+			keys:  map[string]string{"k": ""},
+		},
+		{
+			desp:  "nested slice",
+			value: [][]int{{1, 2}, {3}},
+			keys:  map[string]string{"k[0][0]": "1", "k[0][1]": "2", "k[1][0]": "3"},
+		},
+		{
+			desp:  "array",
+			value: [2]int{1, 2},
+			keys:  map[string]string{"k[0]": "1", "k[1]": "2"},
+		},
+		{
+			desp:  "bytes",
+			value: []byte("hi"),
+			keys:  map[string]string{"k[0]": "104", "k[1]": "105"},
+		},
+		{
+			desp:  "base64 bytes",
+			value: conv.Base64Bytes("hello"),
+			keys:  map[string]string{"k": "aGVsbG8="},
+		},
+		{
+			desp:  "slice of base64 bytes",
+			value: []conv.Base64Bytes{conv.Base64Bytes("a"), conv.Base64Bytes("b")},
+			keys:  map[string]string{"k[0]": "YQ==", "k[1]": "Yg=="},
+		},
+		{
+			desp:  "duration",
+			value: time.Minute,
+			keys:  map[string]string{"k": "1m0s"},
+		},
+		{
+			desp:  "map of duration slice",
+			value: map[string][]time.Duration{"a": {time.Second, time.Minute}},
+			keys:  map[string]string{"k.a[0]": "1s", "k.a[1]": "1m0s"},
+		},
+		{
+			desp:  "map of named key",
+			value: map[testMode]testLevel{"x": 1},
+			keys:  map[string]string{"k.x": "1"},
+		},
+		{
+			desp:  "registered type",
+			value: conv.MiB,
+			keys:  map[string]string{"k": "1MiB"},
+		},
+		{
+			desp:  "text marshaler",
+			value: net.ParseIP("10.0.0.1"),
+			keys:  map[string]string{"k": "10.0.0.1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			p := NewProperties()
+			g.Expect(p.Set(context.Background(), "k", tc.value)).To(Succeed())
+			g.Expect(p.Snapshot()).To(Equal(tc.keys))
+
+			v, err := p.Get(context.Background(), "k", WithType(reflect.TypeOf(tc.value)))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(v).To(Equal(tc.value))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"regexp"
+	"sync"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// Decryptor decrypt the ciphertext of encrypted property value, which is formatted as 'ENC(ciphertext)'.
+type Decryptor interface {
+	// Decrypt return the plaintext of ciphertext.
+	Decrypt(ciphertext string) (string, error)
+}
+
+type namedDecryptor struct {
+	name string
+	d    Decryptor
+}
+
+var (
+	encryptedValueRegex = regexp.MustCompile(`^ENC\((.*)\)$`)
+
+	decryptorsMu sync.RWMutex
+	decryptors   = []namedDecryptor{}
+)
+
+// RegisterDecryptor register the decryptor with name, the decryptor with same name will be replaced.
+// The encrypted value is decrypted by the registered decryptors in order until one of them succeed,
+// so the old key can be kept for rotation.
+func RegisterDecryptor(name string, d Decryptor) {
+	decryptorsMu.Lock()
+	defer decryptorsMu.Unlock()
+
+	for i, nd := range decryptors {
+		if nd.name == name {
+			decryptors[i].d = d
+			return
+		}
+	}
+	decryptors = append(decryptors, namedDecryptor{name: name, d: d})
+}
+
+// UnregisterDecryptor remove the decryptor with name.
+func UnregisterDecryptor(name string) {
+	decryptorsMu.Lock()
+	defer decryptorsMu.Unlock()
+
+	for i, nd := range decryptors {
+		if nd.name == name {
+			decryptors = append(decryptors[:i], decryptors[i+1:]...)
+			return
+		}
+	}
+}
+
+// IsEncrypted return true if the value is formatted as 'ENC(ciphertext)'.
+func IsEncrypted(value string) bool {
+	return encryptedValueRegex.MatchString(value)
+}
+
+// decrypt return the plaintext if value is encrypted, otherwise return the value as it is.
+// Only the whole value is decrypted, e.g. 'ENC(x)' or '${password}' which is resolved to 'ENC(x)'.
+func decrypt(value string) (string, error) {
+	res := encryptedValueRegex.FindStringSubmatch(value)
+	if res == nil {
+		return value, nil
+	}
+
+	decryptorsMu.RLock()
+	defer decryptorsMu.RUnlock()
+
+	if len(decryptors) == 0 {
+		return "", xerrors.Errorf("Cannot decrypt value, no decryptor registered")
+	}
+
+	var err error
+	for _, nd := range decryptors {
+		var plaintext string
+		if plaintext, err = nd.d.Decrypt(res[1]); err == nil {
+			return plaintext, nil
+		}
+	}
+	return "", xerrors.Wrapf(err, "Cannot decrypt value with registered decryptors")
+}
+
+type aesGCMDecryptor struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMDecryptor return the decryptor with AES-GCM, the key must be 16, 24 or 32 bytes.
+// The ciphertext is the standard base64 encoding of nonce, encrypted data and tag.
+func NewAESGCMDecryptor(key []byte) (Decryptor, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &aesGCMDecryptor{aead: aead}, nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "Invalid AES key")
+	}
+	return cipher.NewGCM(block)
+}
+
+func (d *aesGCMDecryptor) Decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", xerrors.Wrapf(err, "Invalid ciphertext, it must be base64 encoded")
+	}
+
+	nonceSize := d.aead.NonceSize()
+	if len(data) < nonceSize {
+		return "", xerrors.Errorf("Invalid ciphertext, it is too short")
+	}
+
+	plaintext, err := d.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return "", xerrors.Wrapf(err, "Cannot open ciphertext")
+	}
+	return string(plaintext), nil
+}
+
+// EncryptAESGCM return the 'ENC(ciphertext)' of plaintext, which can be decrypted by the
+// decryptor of NewAESGCMDecryptor with same key.
+func EncryptAESGCM(key []byte, plaintext string) (string, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	data := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return "ENC(" + base64.StdEncoding.EncodeToString(data) + ")", nil
+}
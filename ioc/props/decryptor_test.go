@@ -0,0 +1,114 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAESGCMDecryptor(t *testing.T) {
+	g := NewWithT(t)
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	value, err := EncryptAESGCM(key, "p@ss")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(IsEncrypted(value)).To(BeTrue())
+
+	d, err := NewAESGCMDecryptor(key)
+	g.Expect(err).ToNot(HaveOccurred())
+	plaintext, err := d.Decrypt(value[len("ENC(") : len(value)-1])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(plaintext).To(Equal("p@ss"))
+
+	_, err = d.Decrypt("!!")
+	g.Expect(err.Error()).To(MatchRegexp("Invalid ciphertext, it must be base64 encoded"))
+	_, err = d.Decrypt("YQ==")
+	g.Expect(err.Error()).To(MatchRegexp("Invalid ciphertext, it is too short"))
+
+	other, err := NewAESGCMDecryptor([]byte("fedcba9876543210"))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = other.Decrypt(value[len("ENC(") : len(value)-1])
+	g.Expect(err.Error()).To(MatchRegexp("Cannot open ciphertext"))
+
+	_, err = NewAESGCMDecryptor([]byte("short"))
+	g.Expect(err.Error()).To(MatchRegexp("Invalid AES key"))
+}
+
+func TestGetEncryptedValue(t *testing.T) {
+	oldKey := []byte("0123456789abcdef")
+	newKey := []byte("fedcba9876543210")
+	oldValue, err := EncryptAESGCM(oldKey, "old")
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+	newValue, err := EncryptAESGCM(newKey, "new")
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	t.Run("no decryptor", func(t *testing.T) {
+		g := NewWithT(t)
+		p := NewProperties()
+		g.Expect(p.Set(context.Background(), "db.password", newValue)).To(Succeed())
+
+		_, err := p.Get(context.Background(), "db.password")
+		g.Expect(err).To(MatchError("Cannot decrypt value, no decryptor registered"))
+	})
+
+	t.Run("decrypt with rotated keys", func(t *testing.T) {
+		g := NewWithT(t)
+		for name, key := range map[string][]byte{"old": oldKey, "new": newKey} {
+			d, err := NewAESGCMDecryptor(key)
+			g.Expect(err).ToNot(HaveOccurred())
+			RegisterDecryptor(name, d)
+			t.Cleanup(func() { UnregisterDecryptor(name) })
+		}
+
+		p := NewProperties()
+		g.Expect(p.Set(context.Background(), "db.password", newValue)).To(Succeed())
+		g.Expect(p.Set(context.Background(), "db.passwords", []string{oldValue, newValue})).To(Succeed())
+		g.Expect(p.Set(context.Background(), "db.ref", "${db.password}")).To(Succeed())
+		g.Expect(p.Set(context.Background(), "db.plain", "ENC(x) not whole")).To(Succeed())
+
+		v, err := p.Get(context.Background(), "db.password")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v).To(Equal("new"))
+
+		var vs []string
+		_, err = p.Get(context.Background(), "db.passwords", WithTarget(&vs))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(vs).To(Equal([]string{"old", "new"}))
+
+		v, err = p.Get(context.Background(), "db.ref")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v).To(Equal("new"))
+
+		v, err = p.Get(context.Background(), "db.missing", WithDefault(oldValue))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v).To(Equal("old"))
+
+		v, err = p.Get(context.Background(), "db.plain")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v).To(Equal("ENC(x) not whole"))
+
+		g.Expect(p.Set(context.Background(), "db.bad", "ENC(YWJj)")).To(Succeed())
+		_, err = p.Get(context.Background(), "db.bad")
+		g.Expect(err.Error()).To(MatchRegexp("Cannot decrypt value with registered decryptors"))
+	})
+}
@@ -0,0 +1,313 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// keyIndex is the index of properties in relaxed binding, it maps the canonical prefix at each
+// boundary of keys to the keys, e.g. 'db.maxPool[0]' is indexed by 'db', 'db.max.pool' and
+// 'db.max.pool[0]', so the relaxed lookup won't canonicalize all keys for each call. The index is
+// built at the first relaxed lookup, so the props must not be modified after that.
+type keyIndex struct {
+	props propertiesImpl
+
+	once     sync.Once
+	prefixes map[string][]prefixedKey
+}
+
+// prefixedKey is the key which prefix is indexed, n is the length of prefix in key.
+type prefixedKey struct {
+	key string
+	n   int
+}
+
+func newKeyIndex(p propertiesImpl) *keyIndex {
+	return &keyIndex{props: p}
+}
+
+// lookup return the keys which prefix is equal to canonicalPrefix in relaxed binding.
+func (x *keyIndex) lookup(canonicalPrefix string) []prefixedKey {
+	x.once.Do(func() {
+		x.prefixes = make(map[string][]prefixedKey, len(x.props))
+		for k := range x.props {
+			for i := 1; i <= len(k); i++ {
+				if !isKeyBoundary(k, i) {
+					continue
+				}
+
+				c := CanonicalKey(k[:i])
+				x.prefixes[c] = append(x.prefixes[c], prefixedKey{key: k, n: i})
+			}
+		}
+	})
+	return x.prefixes[canonicalPrefix]
+}
+
+func (x *keyIndex) doGet(key string) (string, error) {
+	if k, ok := x.findKey(key); ok {
+		return x.props[k], nil
+	}
+
+	return "", xerrors.WrapNotFound("property with key='%v' not found", key)
+}
+
+// findKey return the key in properties which is equal to key, the exact one is preferred
+// and the first sorted one is used if there are multiple keys equal to key in relaxed binding.
+func (x *keyIndex) findKey(key string) (string, bool) {
+	if _, ok := x.props[key]; ok {
+		return key, true
+	}
+
+	found := ""
+	for _, pk := range x.lookup(CanonicalKey(key)) {
+		if pk.n == len(pk.key) && (found == "" || pk.key < found) {
+			found = pk.key
+		}
+	}
+	return found, found != ""
+}
+
+// doGetSlice return the value of key or the values of indexed keys (e.g. 'key[0]', 'key[1]'), the
+// exact key is preferred to the one which is equal in relaxed binding.
+func (x *keyIndex) doGetSlice(key string) ([]string, error) {
+	val, ok := x.props[key]
+	if ok {
+		return []string{val}, nil
+	}
+
+	ret, err := x.indexedValues(key, true)
+	if err != nil || len(ret) > 0 {
+		return ret, err
+	}
+
+	if k, ok := x.findKey(key); ok {
+		return []string{x.props[k]}, nil
+	}
+	ret, err = x.indexedValues(key, false)
+	if err != nil || len(ret) > 0 {
+		return ret, err
+	}
+
+	return nil, xerrors.WrapNotFound("property slice with key='%v' not found", key)
+}
+
+type indexString struct {
+	i int64
+	v string
+}
+
+// indexedValues return the values of direct indexed keys (e.g. 'key[0]') which base key is equal
+// to key, or equal in relaxed binding if exact is false.
+func (x *keyIndex) indexedValues(key string, exact bool) ([]string, error) {
+	ret := []indexString{}
+	for _, pk := range x.lookup(CanonicalKey(key)) {
+		k, n := pk.key, pk.n
+		if n == len(k) || k[n] != '[' || strings.IndexByte(k[n:], ']') != len(k)-n-1 {
+			continue
+		}
+		if exact && k[:n] != key {
+			continue
+		}
+
+		i, err := strconv.ParseInt(k[n+1:len(k)-1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, indexString{
+			i: i,
+			v: x.props[k],
+		})
+	}
+
+	sort.Slice(ret, func(i int, j int) bool {
+		return ret[i].i < ret[j].i
+	})
+
+	rr := make([]string, 0, len(ret))
+	for _, v := range ret {
+		rr = append(rr, v.v)
+	}
+	return rr, nil
+}
+
+// hasKey return true if the key or the indexed key (key[i]) exists, the key is matched in relaxed binding.
+func (x *keyIndex) hasKey(key string) bool {
+	if _, ok := x.props[key]; ok {
+		return true
+	}
+
+	for _, pk := range x.lookup(CanonicalKey(key)) {
+		if pk.n == len(pk.key) || pk.key[pk.n] == '[' {
+			return true
+		}
+	}
+	return false
+}
+
+// childNames return the sorted names of direct children under prefix, e.g. for prefix 'db' and keys
+// ['db.host', 'db.pool.max', 'db.replicas[0].host'], it will return ['host', 'pool', 'replicas'].
+// The prefix is matched in relaxed binding, and the names are kept as they are in keys.
+func (x *keyIndex) childNames(prefix string) []string {
+	names := map[string]struct{}{}
+	addName := func(k string) {
+		if i := strings.IndexAny(k, ".["); i >= 0 {
+			k = k[:i]
+		}
+		if k != "" {
+			names[k] = struct{}{}
+		}
+	}
+
+	if prefix == "" {
+		for k := range x.props {
+			addName(k)
+		}
+	}
+	for _, pk := range x.lookup(CanonicalKey(prefix)) {
+		if pk.n < len(pk.key) && pk.key[pk.n] == '.' {
+			addName(pk.key[pk.n+1:])
+		}
+	}
+
+	ret := make([]string, 0, len(names))
+	for k := range names {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// childIndexes return the sorted indexes of direct children under prefix, e.g. for prefix 'db.replicas'
+// and keys ['db.replicas[0].host', 'db.replicas[1].host'], it will return [0, 1]. The prefix is matched
+// in relaxed binding.
+func (x *keyIndex) childIndexes(prefix string) ([]int64, error) {
+	indexes := map[int64]struct{}{}
+	for _, pk := range x.lookup(CanonicalKey(prefix)) {
+		k, n := pk.key, pk.n
+		if n == len(k) || k[n] != '[' {
+			continue
+		}
+
+		end := strings.Index(k[n:], "]")
+		if end < 0 {
+			continue
+		}
+
+		i, err := strconv.ParseInt(k[n+1:n+end], 10, 64)
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "Invalid index of key '%v'", k)
+		}
+		indexes[i] = struct{}{}
+	}
+
+	ret := make([]int64, 0, len(indexes))
+	for i := range indexes {
+		ret = append(ret, i)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i] < ret[j]
+	})
+	return ret, nil
+}
+
+// Keys return the sorted keys under prefix, which is matched in relaxed binding.
+func (x *keyIndex) Keys(prefix string) []string {
+	if prefix == "" {
+		ret := make([]string, 0, len(x.props))
+		for k := range x.props {
+			ret = append(ret, k)
+		}
+		sort.Strings(ret)
+		return ret
+	}
+
+	keys := map[string]struct{}{}
+	for _, pk := range x.lookup(CanonicalKey(prefix)) {
+		keys[pk.key] = struct{}{}
+	}
+	ret := make([]string, 0, len(keys))
+	for k := range keys {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// replacedKeys return the keys which are replaced by setting val to key. The map or slice value
+// replace the whole subtree, so the shrunk one won't keep stale children, but the scalar value
+// only replace the keys equal to key, so the child keys set separately (e.g. 'X_DB' & 'X_DB_HOST'
+// from env) are kept in any order.
+func (x *keyIndex) replacedKeys(key string, val any) []string {
+	if isCompositeValue(val) {
+		return x.Keys(key)
+	}
+
+	ret := []string{}
+	if _, ok := x.props[key]; ok {
+		ret = append(ret, key)
+	}
+	for _, pk := range x.lookup(CanonicalKey(key)) {
+		if pk.n == len(pk.key) && pk.key != key {
+			ret = append(ret, pk.key)
+		}
+	}
+	return ret
+}
+
+// Has return true if the key or any key under it exists, which is matched in relaxed binding.
+func (x *keyIndex) Has(key string) bool {
+	if key == "" {
+		return len(x.props) > 0
+	}
+	if _, ok := x.props[key]; ok {
+		return true
+	}
+	return len(x.lookup(CanonicalKey(key))) > 0
+}
+
+// Snapshot return the value of all keys, the placeholders are resolved if possible.
+func (x *keyIndex) Snapshot() map[string]string {
+	reader := propertyReader{x}
+	ret := make(map[string]string, len(x.props))
+	for k, v := range x.props {
+		if rv, err := reader.resolve(v); err == nil {
+			v = rv
+		}
+		ret[k] = v
+	}
+	return ret
+}
+
+// Lookup return the raw value of key, which is matched in relaxed binding.
+func (x *keyIndex) Lookup(key string) (string, bool) {
+	k, ok := x.findKey(key)
+	if !ok {
+		return "", false
+	}
+	return x.props[k], true
+}
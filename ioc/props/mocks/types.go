@@ -61,6 +61,46 @@ func (mr *MockPropertiesMockRecorder) Get(ctx, key any, opts ...any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProperties)(nil).Get), varargs...)
 }
 
+// Has mocks base method.
+func (m *MockProperties) Has(key string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Has", key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Has indicates an expected call of Has.
+func (mr *MockPropertiesMockRecorder) Has(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Has", reflect.TypeOf((*MockProperties)(nil).Has), key)
+}
+
+// Keys mocks base method.
+func (m *MockProperties) Keys(prefix string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", prefix)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockPropertiesMockRecorder) Keys(prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockProperties)(nil).Keys), prefix)
+}
+
+// Remove mocks base method.
+func (m *MockProperties) Remove(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Remove", key)
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockPropertiesMockRecorder) Remove(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockProperties)(nil).Remove), key)
+}
+
 // Set mocks base method.
 func (m *MockProperties) Set(ctx context.Context, key string, val any) error {
 	m.ctrl.T.Helper()
@@ -74,3 +114,393 @@ func (mr *MockPropertiesMockRecorder) Set(ctx, key, val any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockProperties)(nil).Set), ctx, key, val)
 }
+
+// SetAll mocks base method.
+func (m *MockProperties) SetAll(ctx context.Context, values map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAll", ctx, values)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAll indicates an expected call of SetAll.
+func (mr *MockPropertiesMockRecorder) SetAll(ctx, values any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAll", reflect.TypeOf((*MockProperties)(nil).SetAll), ctx, values)
+}
+
+// Snapshot mocks base method.
+func (m *MockProperties) Snapshot() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockPropertiesMockRecorder) Snapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockProperties)(nil).Snapshot))
+}
+
+// MockPropertySource is a mock of PropertySource interface.
+type MockPropertySource struct {
+	ctrl     *gomock.Controller
+	recorder *MockPropertySourceMockRecorder
+	isgomock struct{}
+}
+
+// MockPropertySourceMockRecorder is the mock recorder for MockPropertySource.
+type MockPropertySourceMockRecorder struct {
+	mock *MockPropertySource
+}
+
+// NewMockPropertySource creates a new mock instance.
+func NewMockPropertySource(ctrl *gomock.Controller) *MockPropertySource {
+	mock := &MockPropertySource{ctrl: ctrl}
+	mock.recorder = &MockPropertySourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPropertySource) EXPECT() *MockPropertySourceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockPropertySource) Get(ctx context.Context, key string, opts ...props.GetOption) (any, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Get", varargs...)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPropertySourceMockRecorder) Get(ctx, key any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPropertySource)(nil).Get), varargs...)
+}
+
+// Has mocks base method.
+func (m *MockPropertySource) Has(key string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Has", key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Has indicates an expected call of Has.
+func (mr *MockPropertySourceMockRecorder) Has(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Has", reflect.TypeOf((*MockPropertySource)(nil).Has), key)
+}
+
+// Keys mocks base method.
+func (m *MockPropertySource) Keys(prefix string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", prefix)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockPropertySourceMockRecorder) Keys(prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockPropertySource)(nil).Keys), prefix)
+}
+
+// Location mocks base method.
+func (m *MockPropertySource) Location(key string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location", key)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockPropertySourceMockRecorder) Location(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockPropertySource)(nil).Location), key)
+}
+
+// Lookup mocks base method.
+func (m *MockPropertySource) Lookup(key string) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockPropertySourceMockRecorder) Lookup(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockPropertySource)(nil).Lookup), key)
+}
+
+// Name mocks base method.
+func (m *MockPropertySource) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockPropertySourceMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockPropertySource)(nil).Name))
+}
+
+// Remove mocks base method.
+func (m *MockPropertySource) Remove(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Remove", key)
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockPropertySourceMockRecorder) Remove(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockPropertySource)(nil).Remove), key)
+}
+
+// Set mocks base method.
+func (m *MockPropertySource) Set(ctx context.Context, key string, val any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, val)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockPropertySourceMockRecorder) Set(ctx, key, val any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockPropertySource)(nil).Set), ctx, key, val)
+}
+
+// SetAll mocks base method.
+func (m *MockPropertySource) SetAll(ctx context.Context, values map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAll", ctx, values)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAll indicates an expected call of SetAll.
+func (mr *MockPropertySourceMockRecorder) SetAll(ctx, values any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAll", reflect.TypeOf((*MockPropertySource)(nil).SetAll), ctx, values)
+}
+
+// Snapshot mocks base method.
+func (m *MockPropertySource) Snapshot() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockPropertySourceMockRecorder) Snapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockPropertySource)(nil).Snapshot))
+}
+
+// MockConfigurableProperties is a mock of ConfigurableProperties interface.
+type MockConfigurableProperties struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigurablePropertiesMockRecorder
+	isgomock struct{}
+}
+
+// MockConfigurablePropertiesMockRecorder is the mock recorder for MockConfigurableProperties.
+type MockConfigurablePropertiesMockRecorder struct {
+	mock *MockConfigurableProperties
+}
+
+// NewMockConfigurableProperties creates a new mock instance.
+func NewMockConfigurableProperties(ctrl *gomock.Controller) *MockConfigurableProperties {
+	mock := &MockConfigurableProperties{ctrl: ctrl}
+	mock.recorder = &MockConfigurablePropertiesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigurableProperties) EXPECT() *MockConfigurablePropertiesMockRecorder {
+	return m.recorder
+}
+
+// AddPropertySource mocks base method.
+func (m *MockConfigurableProperties) AddPropertySource(source props.PropertySource, precedence props.Precedence) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddPropertySource", source, precedence)
+}
+
+// AddPropertySource indicates an expected call of AddPropertySource.
+func (mr *MockConfigurablePropertiesMockRecorder) AddPropertySource(source, precedence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPropertySource", reflect.TypeOf((*MockConfigurableProperties)(nil).AddPropertySource), source, precedence)
+}
+
+// Get mocks base method.
+func (m *MockConfigurableProperties) Get(ctx context.Context, key string, opts ...props.GetOption) (any, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Get", varargs...)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockConfigurablePropertiesMockRecorder) Get(ctx, key any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockConfigurableProperties)(nil).Get), varargs...)
+}
+
+// Has mocks base method.
+func (m *MockConfigurableProperties) Has(key string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Has", key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Has indicates an expected call of Has.
+func (mr *MockConfigurablePropertiesMockRecorder) Has(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Has", reflect.TypeOf((*MockConfigurableProperties)(nil).Has), key)
+}
+
+// IsConsumed mocks base method.
+func (m *MockConfigurableProperties) IsConsumed(key string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsConsumed", key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsConsumed indicates an expected call of IsConsumed.
+func (mr *MockConfigurablePropertiesMockRecorder) IsConsumed(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsConsumed", reflect.TypeOf((*MockConfigurableProperties)(nil).IsConsumed), key)
+}
+
+// Keys mocks base method.
+func (m *MockConfigurableProperties) Keys(prefix string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", prefix)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockConfigurablePropertiesMockRecorder) Keys(prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockConfigurableProperties)(nil).Keys), prefix)
+}
+
+// Origin mocks base method.
+func (m *MockConfigurableProperties) Origin(ctx context.Context, key string) (props.Origin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Origin", ctx, key)
+	ret0, _ := ret[0].(props.Origin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Origin indicates an expected call of Origin.
+func (mr *MockConfigurablePropertiesMockRecorder) Origin(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Origin", reflect.TypeOf((*MockConfigurableProperties)(nil).Origin), ctx, key)
+}
+
+// PropertySources mocks base method.
+func (m *MockConfigurableProperties) PropertySources() []props.PropertySource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PropertySources")
+	ret0, _ := ret[0].([]props.PropertySource)
+	return ret0
+}
+
+// PropertySources indicates an expected call of PropertySources.
+func (mr *MockConfigurablePropertiesMockRecorder) PropertySources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PropertySources", reflect.TypeOf((*MockConfigurableProperties)(nil).PropertySources))
+}
+
+// Remove mocks base method.
+func (m *MockConfigurableProperties) Remove(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Remove", key)
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockConfigurablePropertiesMockRecorder) Remove(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockConfigurableProperties)(nil).Remove), key)
+}
+
+// RemovePropertySource mocks base method.
+func (m *MockConfigurableProperties) RemovePropertySource(name string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemovePropertySource", name)
+}
+
+// RemovePropertySource indicates an expected call of RemovePropertySource.
+func (mr *MockConfigurablePropertiesMockRecorder) RemovePropertySource(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePropertySource", reflect.TypeOf((*MockConfigurableProperties)(nil).RemovePropertySource), name)
+}
+
+// Set mocks base method.
+func (m *MockConfigurableProperties) Set(ctx context.Context, key string, val any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, val)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockConfigurablePropertiesMockRecorder) Set(ctx, key, val any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockConfigurableProperties)(nil).Set), ctx, key, val)
+}
+
+// SetAll mocks base method.
+func (m *MockConfigurableProperties) SetAll(ctx context.Context, values map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAll", ctx, values)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAll indicates an expected call of SetAll.
+func (mr *MockConfigurablePropertiesMockRecorder) SetAll(ctx, values any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAll", reflect.TypeOf((*MockConfigurableProperties)(nil).SetAll), ctx, values)
+}
+
+// Snapshot mocks base method.
+func (m *MockConfigurableProperties) Snapshot() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockConfigurablePropertiesMockRecorder) Snapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockConfigurableProperties)(nil).Snapshot))
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"strings"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+const (
+	placeholderPrefix  = "${"
+	placeholderSuffix  = "}"
+	placeholderDefault = ":="
+	placeholderEscape  = `\${`
+)
+
+// ResolvePlaceholders will expand the '${key}' and '${key:=default}' placeholders in value recursively,
+// the value of key is retrieved by lookup and it may contain placeholders too. The '\${' is escaped as
+// literal '${'. It will return error if the placeholder cannot be resolved or is referenced circularly.
+func ResolvePlaceholders(value string, lookup func(key string) (string, bool)) (string, error) {
+	return resolvePlaceholders(value, lookup, nil)
+}
+
+func resolvePlaceholders(value string, lookup func(string) (string, bool), visiting []string) (string, error) {
+	if !strings.Contains(value, placeholderPrefix) {
+		return value, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(value); {
+		if strings.HasPrefix(value[i:], placeholderEscape) {
+			sb.WriteString(placeholderPrefix)
+			i += len(placeholderEscape)
+			continue
+		}
+
+		if !strings.HasPrefix(value[i:], placeholderPrefix) {
+			sb.WriteByte(value[i])
+			i++
+			continue
+		}
+
+		end := findPlaceholderEnd(value, i)
+		if end < 0 {
+			// The placeholder isn't closed, we treat it as literal
+			sb.WriteString(value[i:])
+			break
+		}
+
+		v, err := resolvePlaceholder(value[i+len(placeholderPrefix):end], lookup, visiting)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(v)
+		i = end + len(placeholderSuffix)
+	}
+
+	return sb.String(), nil
+}
+
+func resolvePlaceholder(content string, lookup func(string) (string, bool), visiting []string) (string, error) {
+	name, def, hasDefault := splitPlaceholder(content)
+
+	// The name may also contain placeholders, e.g. ${${env}.host}
+	name, err := resolvePlaceholders(name, lookup, visiting)
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range visiting {
+		if v == name {
+			return "", xerrors.Errorf(
+				"Circular placeholder reference '%v'", strings.Join(append(visiting, name), " -> "))
+		}
+	}
+
+	raw, ok := lookup(name)
+	if !ok {
+		if !hasDefault {
+			return "", xerrors.Errorf("Could not resolve placeholder '${%v}'", content)
+		}
+
+		return resolvePlaceholders(def, lookup, visiting)
+	}
+
+	return resolvePlaceholders(raw, lookup, append(visiting[:len(visiting):len(visiting)], name))
+}
+
+// findPlaceholderEnd return the index of suffix which match the placeholder prefix at start,
+// it return -1 if the placeholder isn't closed.
+func findPlaceholderEnd(value string, start int) int {
+	depth := 0
+	for i := start; i < len(value); {
+		switch {
+		case strings.HasPrefix(value[i:], placeholderEscape):
+			i += len(placeholderEscape)
+			continue
+		case strings.HasPrefix(value[i:], placeholderPrefix):
+			depth++
+			i += len(placeholderPrefix)
+			continue
+		case strings.HasPrefix(value[i:], placeholderSuffix):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+		i++
+	}
+
+	return -1
+}
+
+// splitPlaceholder split the placeholder content into name and default value by the
+// first ':=' which isn't in the nested placeholder.
+func splitPlaceholder(content string) (string, string, bool) {
+	depth := 0
+	for i := 0; i < len(content); i++ {
+		switch {
+		case strings.HasPrefix(content[i:], placeholderEscape):
+			i += len(placeholderEscape) - 1
+		case strings.HasPrefix(content[i:], placeholderPrefix):
+			depth++
+			i++
+		case strings.HasPrefix(content[i:], placeholderSuffix):
+			depth--
+		case depth == 0 && strings.HasPrefix(content[i:], placeholderDefault):
+			return content[:i], content[i+len(placeholderDefault):], true
+		}
+	}
+
+	return content, "", false
+}
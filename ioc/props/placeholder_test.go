@@ -0,0 +1,156 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestResolvePlaceholders(t *testing.T) {
+	values := map[string]string{
+		"host":   "localhost",
+		"port":   "8080",
+		"url":    "http://${host}:${port}",
+		"env":    "dev",
+		"dev.db": "db-dev",
+		"a":      "${b}",
+		"b":      "${c}",
+		"c":      "${a}",
+		"self":   "${self}",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+
+	type testCase struct {
+		desp   string
+		value  string
+		expect string
+		err    string
+	}
+	testCases := []testCase{
+		{
+			desp:   "without placeholder",
+			value:  "http://localhost",
+			expect: "http://localhost",
+		},
+		{
+			desp:   "single placeholder",
+			value:  "${host}",
+			expect: "localhost",
+		},
+		{
+			desp:   "nested reference",
+			value:  "url=${url}/api",
+			expect: "url=http://localhost:8080/api",
+		},
+		{
+			desp:   "default value",
+			value:  "${missing:=x}",
+			expect: "x",
+		},
+		{
+			desp:   "empty default value",
+			value:  "[${missing:=}]",
+			expect: "[]",
+		},
+		{
+			desp:   "nested default value",
+			value:  "${missing:=${host}}",
+			expect: "localhost",
+		},
+		{
+			desp:   "nested default with default",
+			value:  "${missing:=${missing2:=${port}}}",
+			expect: "8080",
+		},
+		{
+			desp:   "placeholder in name",
+			value:  "${${env}.db}",
+			expect: "db-dev",
+		},
+		{
+			desp:   "escaped placeholder",
+			value:  `\${host} is ${host}`,
+			expect: "${host} is localhost",
+		},
+		{
+			desp:   "escaped placeholder in default",
+			value:  `${missing:=\${host}}`,
+			expect: "${host}",
+		},
+		{
+			desp:   "unclosed placeholder",
+			value:  "${host",
+			expect: "${host",
+		},
+		{
+			desp:   "dollar without brace",
+			value:  "$host",
+			expect: "$host",
+		},
+		{
+			desp:  "unresolvable placeholder",
+			value: "${missing}",
+			err:   `Could not resolve placeholder '\${missing}'`,
+		},
+		{
+			desp:  "circular reference",
+			value: "${a}",
+			err:   "Circular placeholder reference 'a -> b -> c -> a'",
+		},
+		{
+			desp:  "self reference",
+			value: "${self}",
+			err:   "Circular placeholder reference 'self -> self'",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := ResolvePlaceholders(tc.value, lookup)
+			if tc.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(MatchRegexp(tc.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(actual).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestSplitPlaceholder(t *testing.T) {
+	g := NewWithT(t)
+
+	name, def, ok := splitPlaceholder("a:=${b:=c}")
+	g.Expect([]any{name, def, ok}).To(Equal([]any{"a", "${b:=c}", true}))
+
+	name, def, ok = splitPlaceholder("${a:=b}")
+	g.Expect([]any{name, def, ok}).To(Equal([]any{"${a:=b}", "", false}))
+
+	name, def, ok = splitPlaceholder("${a:=b}:=c")
+	g.Expect([]any{name, def, ok}).To(Equal([]any{"${a:=b}", "c", true}))
+}
@@ -24,8 +24,6 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
-	"sort"
-	"strconv"
 	"strings"
 
 	"github.com/anyvoxel/airmid/anvil/conv"
@@ -34,15 +32,28 @@ import (
 	slogctx "github.com/veqryn/slog-context"
 )
 
-// NewProperties return the Properties impl.
-func NewProperties() Properties {
-	return propertiesImpl(make(map[string]string))
+// propertyStore is the flattened key-value storage, which the property value is read from.
+type propertyStore interface {
+	doGet(key string) (string, error)
+	doGetSlice(key string) ([]string, error)
+	hasKey(key string) bool
+	childNames(prefix string) []string
+	childIndexes(prefix string) ([]int64, error)
+}
+
+// propertyReader read & convert the property value from store.
+type propertyReader struct {
+	propertyStore
 }
 
 type propertiesImpl map[string]string
 
+func (p propertiesImpl) Get(ctx context.Context, key string, opts ...GetOption) (any, error) {
+	return propertyReader{p}.Get(ctx, key, opts...)
+}
+
 //nolint:revive,cyclop
-func (p propertiesImpl) Get(ctx context.Context, key string, opts ...GetOption) (ret any, err error) {
+func (p propertyReader) Get(ctx context.Context, key string, opts ...GetOption) (ret any, err error) {
 	opt := defaultGetOption()
 	for _, o := range opts {
 		o.Apply(opt)
@@ -60,18 +71,22 @@ func (p propertiesImpl) Get(ctx context.Context, key string, opts ...GetOption)
 
 	var propValues []string
 	switch {
-	case targetValue.Kind() == reflect.Slice:
-		vstrs, err := p.doGetSlice(key)
+	case isBindableType(targetValue.Type()):
+		// The struct, map or slice of them cannot be converted from string, we must bind it
+		// with the nested keys.
+		found, err := p.bindValue(ctx, key, targetValue, opt.Default)
 		if err != nil {
-			if !xerrors.Is(err, xerrors.ErrNotFound) || opt.Default == nil {
-				return nil, err
-			}
-
-			if *opt.Default == "" {
-				vstrs = []string{}
-			} else {
-				vstrs = strings.Split(*opt.Default, ",")
-			}
+			return nil, err
+		}
+		if !found && opt.Default == nil && targetValue.Kind() == reflect.Map {
+			return nil, xerrors.WrapNotFound("property map with key='%v' not found", key)
+		}
+		return opt.Target.Interface(), nil
+	case (targetValue.Kind() == reflect.Slice || targetValue.Kind() == reflect.Array) &&
+		!conv.HasConverter(targetValue.Type()):
+		vstrs, err := p.getSlice(key, opt.Default)
+		if err != nil {
+			return nil, err
 		}
 		propValues = vstrs
 	default:
@@ -83,6 +98,10 @@ func (p propertiesImpl) Get(ctx context.Context, key string, opts ...GetOption)
 
 			vstr = *opt.Default
 		}
+
+		if vstr, err = p.resolve(vstr); err != nil {
+			return nil, err
+		}
 		propValues = []string{vstr}
 	}
 
@@ -94,68 +113,162 @@ func (p propertiesImpl) Get(ctx context.Context, key string, opts ...GetOption)
 	return opt.Target.Interface(), nil
 }
 
-func (p propertiesImpl) doGet(key string) (string, error) {
-	val, ok := p[key]
-	if ok {
-		return val, nil
+// splitDefault return the elements of default value, which is separated by ','.
+func splitDefault(def string) []string {
+	if def == "" {
+		return []string{}
 	}
 
-	return "", xerrors.WrapNotFound("property with key='%v' not found", key)
+	return strings.Split(def, ",")
 }
 
-type indexString struct {
-	i int64
-	v string
-}
+// getSlice return the resolved slice value of key, the default value is used when key is not found.
+// The empty value of key is the empty slice, which is set by the empty slice value.
+func (p propertyReader) getSlice(key string, def *string) ([]string, error) {
+	vstrs, err := p.doGetSlice(key)
+	if err == nil {
+		if len(vstrs) == 1 && vstrs[0] == "" {
+			return []string{}, nil
+		}
+		return p.resolveAll(vstrs)
+	}
 
-func (p propertiesImpl) doGetSlice(key string) ([]string, error) {
-	val, ok := p[key]
-	if ok {
-		return []string{val}, nil
+	if !xerrors.Is(err, xerrors.ErrNotFound) || def == nil {
+		return nil, err
 	}
 
-	ret := []indexString{}
-	for k, v := range p {
-		if !strings.HasPrefix(k, key) {
-			continue
-		}
+	vstr, err := p.resolve(*def)
+	if err != nil {
+		return nil, err
+	}
+	return splitDefault(vstr), nil
+}
 
-		k = k[len(key):]
-		if !strings.HasPrefix(k, "[") || !strings.HasSuffix(k, "]") {
-			continue
-		}
+// resolve will expand the placeholders in value with the properties, and decrypt it if the
+// resolved value is encrypted.
+func (p propertyReader) resolve(value string) (string, error) {
+	value, err := ResolvePlaceholders(value, p.lookup)
+	if err != nil {
+		return "", err
+	}
+	return decrypt(value)
+}
 
-		k = k[1 : len(k)-1]
-		i, err := strconv.ParseInt(k, 10, 64)
+func (p propertyReader) resolveAll(values []string) ([]string, error) {
+	ret := make([]string, 0, len(values))
+	for _, v := range values {
+		rv, err := p.resolve(v)
 		if err != nil {
 			return nil, err
 		}
+		ret = append(ret, rv)
+	}
+	return ret, nil
+}
 
-		ret = append(ret, indexString{
-			i: i,
-			v: v,
-		})
+// lookup return the raw value of key for placeholder, the slice value will be joined with ','.
+func (p propertyReader) lookup(key string) (string, bool) {
+	vstrs, err := p.doGetSlice(key)
+	if err != nil {
+		return "", false
 	}
 
-	sort.Slice(ret, func(i int, j int) bool {
-		return ret[i].i < ret[j].i
-	})
+	return strings.Join(vstrs, ","), true
+}
+
+func (p propertiesImpl) doGet(key string) (string, error) {
+	return newKeyIndex(p).doGet(key)
+}
+
+func (p propertiesImpl) findKey(key string) (string, bool) {
+	return newKeyIndex(p).findKey(key)
+}
+
+func (p propertiesImpl) doGetSlice(key string) ([]string, error) {
+	return newKeyIndex(p).doGetSlice(key)
+}
+
+func (p propertiesImpl) Set(ctx context.Context, key string, val any) error {
+	return p.SetAll(ctx, map[string]any{key: val})
+}
 
-	if len(ret) > 0 {
-		rr := make([]string, 0, len(ret))
-		for _, v := range ret {
-			rr = append(rr, v.v)
+// SetAll set the flattened values, the replaced keys are described by keyIndex.replacedKeys, nothing
+// is changed if any value cannot be flattened.
+func (p propertiesImpl) SetAll(ctx context.Context, values map[string]any) error {
+	flattened := propertiesImpl{}
+	for key, val := range values {
+		err := flattenValue(ctx, key, val, func(k string, v string) {
+			flattened[k] = v
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	index := newKeyIndex(p)
+	for key, val := range values {
+		for _, k := range index.replacedKeys(key, val) {
+			delete(p, k)
 		}
+	}
+	for k, v := range flattened {
+		p[k] = v
+	}
+	return nil
+}
 
-		return rr, nil
+func (p propertiesImpl) Keys(prefix string) []string {
+	return newKeyIndex(p).Keys(prefix)
+}
+
+func (p propertiesImpl) Has(key string) bool {
+	return newKeyIndex(p).Has(key)
+}
+
+func (p propertiesImpl) Remove(key string) {
+	for _, k := range p.Keys(key) {
+		delete(p, k)
 	}
+}
 
-	return nil, xerrors.WrapNotFound("property slice with key='%v' not found", key)
+func (p propertiesImpl) Snapshot() map[string]string {
+	return newKeyIndex(p).Snapshot()
 }
 
+func (p propertiesImpl) Lookup(key string) (string, bool) {
+	return newKeyIndex(p).Lookup(key)
+}
+
+// isCompositeValue return true if the val is expanded into nested or indexed keys by flattenValue.
+//
+//nolint:exhaustive
+func isCompositeValue(val any) bool {
+	if val == nil {
+		return false
+	}
+
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Map, reflect.Array, reflect.Slice:
+		return !conv.HasToString(v.Type())
+	default:
+		return false
+	}
+}
+
+// flattenValue will expand the map & slice value into flattened keys (e.g. 'key.sub', 'key[0]'),
+// and store the string value of each key with fn.
+//
 //nolint:revive,exhaustive
-func (p propertiesImpl) Set(ctx context.Context, key string, val any) error {
-	switch v := reflect.ValueOf(val); v.Kind() {
+func flattenValue(ctx context.Context, key string, val any, fn func(key string, value string)) error {
+	v := reflect.ValueOf(val)
+	kind := v.Kind()
+	if val != nil && conv.HasToString(v.Type()) {
+		// The registered type (e.g. net.IP) is converted to string even if it is slice
+		kind = reflect.Invalid
+	}
+
+	switch kind {
 	case reflect.Map:
 		// If the val is a map, we expand the val with keys and set it recursive
 		for _, k := range v.MapKeys() {
@@ -166,17 +279,22 @@ func (p propertiesImpl) Set(ctx context.Context, key string, val any) error {
 
 			kstr = fmt.Sprintf("%s.%s", key, kstr)
 			kvalue := v.MapIndex(k).Interface()
-			err = p.Set(ctx, kstr, kvalue)
+			err = flattenValue(ctx, kstr, kvalue, fn)
 			if err != nil {
 				return xerrors.Wrapf(err, "Cannot set val for map's key '%v'", kstr)
 			}
 		}
 	case reflect.Array, reflect.Slice:
-		// If the val is a array/slice, we expand the val with index and set it recursive
+		// If the val is a array/slice, we expand the val with index and set it recursive,
+		// the empty one is set as empty string, so it can be read as empty slice.
+		if v.Len() == 0 {
+			fn(key, "")
+			return nil
+		}
 		for i := 0; i < v.Len(); i++ {
 			kstr := fmt.Sprintf("%s[%d]", key, i)
 			kvalue := v.Index(i).Interface()
-			err := p.Set(ctx, kstr, kvalue)
+			err := flattenValue(ctx, kstr, kvalue, fn)
 			if err != nil {
 				return xerrors.Wrapf(err, "Cannot set val for array/slice index's key '%v'", kstr)
 			}
@@ -186,12 +304,12 @@ func (p propertiesImpl) Set(ctx context.Context, key string, val any) error {
 		if err != nil {
 			return xerrors.Wrapf(err, "Cannot convert value for key '%s' to string", key)
 		}
-		p[key] = value
+		fn(key, value)
 		slogctx.FromCtx(ctx).DebugContext(
 			ctx,
 			"set property success",
 			slog.String("Key", key),
-			slog.String("Value", value),
+			slog.String("Value", Redact(key, value)),
 		)
 	}
 
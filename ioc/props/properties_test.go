@@ -252,7 +252,7 @@ func TestSet(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.desp, func(t *testing.T) {
 			g := NewWithT(t)
-			p := NewProperties().(propertiesImpl)
+			p := propertiesImpl{}
 			err := p.Set(context.Background(), tc.key, tc.value)
 			if tc.err != "" {
 				g.Expect(err).To(HaveOccurred())
@@ -538,8 +538,8 @@ func TestGet(t *testing.T) {
 				"k2": "2",
 			}),
 			key: "k1",
-			typ: reflect.TypeOf(testCase{}),
-			err: "Unsupport target type props.testCase",
+			typ: reflect.TypeOf(make(chan int)),
+			err: "Unsupport target type chan int",
 		},
 		{
 			desp: "target to struct ptr value",
@@ -670,6 +670,189 @@ func TestGet(t *testing.T) {
 				return &v
 			}(),
 		},
+		{
+			desp: "get map[string]string with child keys",
+			p: propertiesImpl(map[string]string{
+				"k1.a": "1",
+				"k1.b": "2",
+				"k2":   "2",
+			}),
+			key: "k1",
+			typ: reflect.TypeOf(map[string]string{}),
+			err: "",
+			expect: map[string]string{
+				"a": "1",
+				"b": "2",
+			},
+		},
+		{
+			desp: "get map[string][]int with nested keys",
+			p: propertiesImpl(map[string]string{
+				"k1.a[0]": "1",
+				"k1.a[1]": "2",
+				"k1.b":    "3",
+			}),
+			key: "k1",
+			typ: reflect.TypeOf(map[string][]int{}),
+			err: "",
+			expect: map[string][]int{
+				"a": {1, 2},
+				"b": {3},
+			},
+		},
+		{
+			desp: "get map[int]map[string]bool with nested keys",
+			p: propertiesImpl(map[string]string{
+				"k1.1.a": "true",
+				"k1.2.b": "false",
+			}),
+			key: "k1",
+			typ: reflect.TypeOf(map[int]map[string]bool{}),
+			err: "",
+			expect: map[int]map[string]bool{
+				1: {"a": true},
+				2: {"b": false},
+			},
+		},
+		{
+			desp: "get map[string]int with indexed entries",
+			p: propertiesImpl(map[string]string{
+				"k1[0]": "a=1",
+				"k1[1]": "b=2",
+			}),
+			key: "k1",
+			typ: reflect.TypeOf(map[string]int{}),
+			err: "",
+			expect: map[string]int{
+				"a": 1,
+				"b": 2,
+			},
+		},
+		{
+			desp: "get map with default",
+			p: propertiesImpl(map[string]string{
+				"k2": "2",
+			}),
+			key: "k1",
+			typ: reflect.TypeOf(map[string]int{}),
+			def: pointer.StringPtr("a=1,b=2"),
+			err: "",
+			expect: map[string]int{
+				"a": 1,
+				"b": 2,
+			},
+		},
+		{
+			desp: "get map with empty default",
+			p: propertiesImpl(map[string]string{
+				"k2": "2",
+			}),
+			key:    "k1",
+			typ:    reflect.TypeOf(map[string]int{}),
+			def:    pointer.StringPtr(""),
+			err:    "",
+			expect: map[string]int{},
+		},
+		{
+			desp: "get map not found",
+			p: propertiesImpl(map[string]string{
+				"k2": "2",
+			}),
+			key: "k1",
+			typ: reflect.TypeOf(map[string]int{}),
+			err: "property map with key='k1' not found",
+		},
+		{
+			desp: "get map convert value failed",
+			p: propertiesImpl(map[string]string{
+				"k1.a": "x",
+			}),
+			key: "k1",
+			typ: reflect.TypeOf(map[string]int{}),
+			err: "Cannot bind property 'k1.a'",
+		},
+		{
+			desp: "get map convert key failed",
+			p: propertiesImpl(map[string]string{
+				"k1.a": "1",
+			}),
+			key: "k1",
+			typ: reflect.TypeOf(map[int]int{}),
+			err: "Cannot convert map's key 'a' to int",
+		},
+		{
+			desp: "get value with placeholder",
+			p: propertiesImpl(map[string]string{
+				"host": "localhost",
+				"port": "8080",
+				"url":  "http://${host}:${port}",
+			}),
+			key:    "url",
+			err:    "",
+			expect: "http://localhost:8080",
+		},
+		{
+			desp: "get int with placeholder",
+			p: propertiesImpl(map[string]string{
+				"port":        "8080",
+				"server.port": "${port}",
+			}),
+			key:    "server.port",
+			typ:    reflect.TypeOf(0),
+			err:    "",
+			expect: 8080,
+		},
+		{
+			desp: "get default with placeholder",
+			p: propertiesImpl(map[string]string{
+				"port": "8080",
+			}),
+			key:    "server.port",
+			def:    pointer.StringPtr("${port}"),
+			err:    "",
+			expect: "8080",
+		},
+		{
+			desp: "get slice with placeholder",
+			p: propertiesImpl(map[string]string{
+				"host":     "localhost",
+				"hosts[0]": "${host}:80",
+				"hosts[1]": "${host}:81",
+			}),
+			key:    "hosts",
+			typ:    reflect.TypeOf([]string{}),
+			err:    "",
+			expect: []string{"localhost:80", "localhost:81"},
+		},
+		{
+			desp: "get slice default with slice placeholder",
+			p: propertiesImpl(map[string]string{
+				"hosts[0]": "a",
+				"hosts[1]": "b",
+			}),
+			key:    "servers",
+			typ:    reflect.TypeOf([]string{}),
+			def:    pointer.StringPtr("${hosts}"),
+			err:    "",
+			expect: []string{"a", "b"},
+		},
+		{
+			desp: "get value with circular placeholder",
+			p: propertiesImpl(map[string]string{
+				"a": "${b}",
+				"b": "${a}",
+			}),
+			key: "a",
+			err: "Circular placeholder reference 'b -> a -> b'",
+		},
+		{
+			desp: "get value with unresolvable placeholder",
+			p: propertiesImpl(map[string]string{
+				"a": "${b}",
+			}),
+			key: "a",
+			err: `Could not resolve placeholder '\${b}'`,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desp, func(t *testing.T) {
@@ -770,3 +953,19 @@ func TestDoGetSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestSetAndGetMap(t *testing.T) {
+	g := NewWithT(t)
+
+	p := NewProperties()
+	expect := map[string][]time.Duration{
+		"a": {time.Second, time.Minute},
+		"b": {time.Hour},
+	}
+	err := p.Set(context.Background(), "k1", expect)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	actual, err := p.Get(context.Background(), "k1", WithType(reflect.TypeOf(map[string][]time.Duration{})))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(actual).To(Equal(expect))
+}
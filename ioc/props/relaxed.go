@@ -0,0 +1,67 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CanonicalKey return the canonical form of key for relaxed binding, the words separated by '.',
+// '-', '_' or camel case are lowercased and joined by '.', so 'a.b-c', 'a.b_c', 'a.bC' and 'A_B_C'
+// are all resolved as 'a.b.c'. The index of key (e.g. '[0]') is kept as it is.
+func CanonicalKey(key string) string {
+	var b strings.Builder
+	b.Grow(len(key))
+
+	// sep is true if the separator should be written before the next word rune
+	sep := false
+	prevLowerOrDigit := false
+	inIndex := false
+	for _, r := range key {
+		switch {
+		case inIndex:
+			b.WriteRune(r)
+			inIndex = r != ']'
+		case r == '[':
+			b.WriteRune(r)
+			inIndex, sep, prevLowerOrDigit = true, false, false
+		case r == '.' || r == '-' || r == '_':
+			sep = b.Len() > 0
+			prevLowerOrDigit = false
+		default:
+			if unicode.IsUpper(r) && prevLowerOrDigit {
+				sep = true
+			}
+			if sep {
+				b.WriteByte('.')
+				sep = false
+			}
+			b.WriteRune(unicode.ToLower(r))
+			prevLowerOrDigit = unicode.IsLower(r) || unicode.IsDigit(r)
+		}
+	}
+	return b.String()
+}
+
+// isKeyBoundary return true if the i is the end of key or the start of sub key or index.
+func isKeyBoundary(key string, i int) bool {
+	return i == len(key) || key[i] == '.' || key[i] == '['
+}
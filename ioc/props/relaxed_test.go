@@ -0,0 +1,137 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCanonicalKey(t *testing.T) {
+	type testCase struct {
+		desp   string
+		key    string
+		expect string
+	}
+	testCases := []testCase{
+		{desp: "dot", key: "a.b.c", expect: "a.b.c"},
+		{desp: "dash", key: "a.b-c", expect: "a.b.c"},
+		{desp: "underscore", key: "a.b_c", expect: "a.b.c"},
+		{desp: "camel case", key: "a.bC", expect: "a.b.c"},
+		{desp: "upper case", key: "A_B_C", expect: "a.b.c"},
+		{desp: "acronym", key: "readHTTPTimeout", expect: "read.httptimeout"},
+		{desp: "digit", key: "v2Api", expect: "v2.api"},
+		{desp: "repeated separators", key: "-a..b__c-", expect: "a.b.c"},
+		{desp: "index", key: "servers[0].maxConn[A_b]", expect: "servers[0].max.conn[A_b]"},
+		{desp: "empty", key: "", expect: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(CanonicalKey(tc.key)).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestRelaxedGet(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	p := NewProperties()
+	config := NewPropertySource("config")
+	g.Expect(config.Set(ctx, "http.read-timeout", "1s")).To(Succeed())
+	g.Expect(config.Set(ctx, "http.write-timeout", "2s")).To(Succeed())
+	g.Expect(config.Set(ctx, "http.hosts", []string{"a", "b"})).To(Succeed())
+	g.Expect(config.Set(ctx, "labels.app_name", "x")).To(Succeed())
+	p.AddPropertySource(config, PrecedenceConfigFile)
+	env := NewPropertySource("env")
+	g.Expect(env.Set(ContextWithLocation(ctx, func(string) string {
+		return "AIRMID_HTTP_READ_TIMEOUT"
+	}), "http.read.timeout", "3s")).To(Succeed())
+	p.AddPropertySource(env, PrecedenceEnv)
+
+	// The relaxed key in higher source take precedence over the exact key in lower one
+	for _, key := range []string{"http.read-timeout", "http.read_timeout", "http.readTimeout", "HTTP_READ_TIMEOUT"} {
+		g.Expect(p.Get(ctx, key)).To(Equal("3s"), key)
+	}
+	origin, err := p.Origin(ctx, "http.readTimeout")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(origin).To(Equal(Origin{Source: "env", Location: "AIRMID_HTTP_READ_TIMEOUT"}))
+
+	var hosts []string
+	_, err = p.Get(ctx, "HTTP.Hosts", WithTarget(&hosts))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hosts).To(Equal([]string{"a", "b"}))
+
+	type testHTTP struct {
+		ReadTimeout  time.Duration
+		WriteTimeout time.Duration
+	}
+	type testConfig struct {
+		HTTP   testHTTP `prop:"http"`
+		Labels map[string]string
+	}
+	var c testConfig
+	g.Expect(Bind(ctx, p, "", &c)).To(Succeed())
+	g.Expect(c).To(Equal(testConfig{
+		HTTP:   testHTTP{ReadTimeout: 3 * time.Second, WriteTimeout: 2 * time.Second},
+		Labels: map[string]string{"app_name": "x"},
+	}))
+	g.Expect(p.IsConsumed("http.read.timeout")).To(BeTrue())
+	g.Expect(p.IsConsumed("http.write-timeout")).To(BeTrue())
+
+	_, err = p.Get(ctx, "http.readtimeouts")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestKeyIndex(t *testing.T) {
+	g := NewWithT(t)
+
+	x := newKeyIndex(propertiesImpl{
+		"db.maxPool":          "10",
+		"db.replicas[0].host": "a",
+		"db.replicas[1].host": "b",
+		"DB_USER":             "root",
+		"hosts[0]":            "h0",
+		"hosts[1]":            "h1",
+	})
+	g.Expect(x.lookup("db.max.pool")).To(Equal([]prefixedKey{{key: "db.maxPool", n: 10}}))
+
+	k, ok := x.findKey("db.max-pool")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(k).To(Equal("db.maxPool"))
+	_, ok = x.findKey("db")
+	g.Expect(ok).To(BeFalse())
+
+	g.Expect(x.hasKey("db.user")).To(BeTrue())
+	g.Expect(x.hasKey("HOSTS")).To(BeTrue())
+	g.Expect(x.hasKey("db.replicas[0]")).To(BeFalse())
+	g.Expect(x.childNames("db")).To(Equal([]string{"maxPool", "replicas"}))
+	g.Expect(x.childNames("")).To(Equal([]string{"DB_USER", "db", "hosts"}))
+	g.Expect(x.childIndexes("dbReplicas")).To(Equal([]int64{0, 1}))
+	g.Expect(x.doGetSlice("HOSTS")).To(Equal([]string{"h0", "h1"}))
+	g.Expect(x.Keys("db.replicas")).To(Equal([]string{"db.replicas[0].host", "db.replicas[1].host"}))
+	g.Expect(x.Has("db.replicas[1]")).To(BeTrue())
+	g.Expect(x.Has("db.pool")).To(BeFalse())
+}
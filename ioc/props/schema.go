@@ -0,0 +1,190 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/anyvoxel/airmid/anvil/conv"
+)
+
+const (
+	// DescriptionTagName is the tag name to describe the property of field, e.g. `desc:"the listen port"`,
+	// it is used in the schema of properties.
+	DescriptionTagName string = "desc"
+)
+
+// durationPattern is the pattern of time.Duration string, e.g. '1h30m', '0.5s'.
+const durationPattern = `^[-+]?(0|([0-9]*(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+)$`
+
+// JSONSchema return the JSON Schema of typ which is the target of property, the def is the
+// default value of property. The struct is described with its fields as same as binding, and
+// the PropertyHandle (e.g. Value[T]) is described as T.
+func JSONSchema(typ reflect.Type, def *string) map[string]any {
+	s := (&schemaBuilder{visiting: map[reflect.Type]bool{}}).build(typ)
+	if def != nil {
+		if v, ok := schemaDefault(typ, *def); ok {
+			s["default"] = v
+		}
+	}
+	return s
+}
+
+type schemaBuilder struct {
+	// visiting is the struct types which are being built, to break the recursive types
+	visiting map[reflect.Type]bool
+}
+
+//nolint:exhaustive,cyclop
+func (b *schemaBuilder) build(typ reflect.Type) map[string]any {
+	typ = indirectHandleType(typ)
+	if typ == reflect.TypeOf(time.Duration(0)) {
+		return map[string]any{"type": "string", "pattern": durationPattern}
+	}
+	if conv.HasConverter(typ) {
+		// The registered type is converted from string, e.g. time.Time, net.IP
+		return map[string]any{"type": "string"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		// The list can be set by the separated string too, e.g. 'a,b' in env or default
+		return map[string]any{"anyOf": []any{
+			map[string]any{"type": "array", "items": b.build(typ.Elem())},
+			map[string]any{"type": "string"},
+		}}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.build(typ.Elem())}
+	case reflect.Struct:
+		return b.buildStruct(typ)
+	}
+	return map[string]any{}
+}
+
+func (b *schemaBuilder) buildStruct(typ reflect.Type) map[string]any {
+	if b.visiting[typ] {
+		return map[string]any{"type": "object"}
+	}
+	b.visiting[typ] = true
+	defer delete(b.visiting, typ)
+
+	properties := map[string]any{}
+	b.buildFields(typ, properties)
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+func (b *schemaBuilder) buildFields(typ reflect.Type, properties map[string]any) {
+	for idx := 0; idx < typ.NumField(); idx++ {
+		field := typ.Field(idx)
+		name, def, ok := fieldPropName(field)
+		if !ok {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			// The embedded struct without prop tag is squashed into parent
+			if ft := indirectHandleType(field.Type); ft.Kind() == reflect.Struct {
+				b.buildFields(ft, properties)
+			}
+			continue
+		}
+
+		s := b.build(field.Type)
+		if def != nil {
+			if v, ok := schemaDefault(field.Type, *def); ok {
+				s["default"] = v
+			}
+		}
+		if desc, ok := field.Tag.Lookup(DescriptionTagName); ok {
+			s["description"] = desc
+		}
+		properties[name] = s
+	}
+}
+
+// indirectHandleType return the element type of pointer, and the value type of PropertyHandle.
+func indirectHandleType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	handleType := reflect.TypeOf((*PropertyHandle)(nil)).Elem()
+	if !reflect.PointerTo(typ).Implements(handleType) {
+		return typ
+	}
+	// The value type is the first result of Get, e.g. Value[T].Get(ctx) (T, error)
+	m, ok := reflect.PointerTo(typ).MethodByName("Get")
+	if !ok || m.Type.NumOut() == 0 {
+		return typ
+	}
+	return indirectHandleType(m.Type.Out(0))
+}
+
+// schemaDefault return the default value in JSON representation, the default which cannot be
+// converted (e.g. contains placeholder) is ignored.
+//
+//nolint:exhaustive
+func schemaDefault(typ reflect.Type, def string) (any, bool) {
+	if strings.Contains(def, "${") {
+		return nil, false
+	}
+
+	typ = indirectHandleType(typ)
+	if typ == reflect.TypeOf(time.Duration(0)) || conv.HasConverter(typ) {
+		return def, true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		v, err := conv.ConvertTo(context.Background(), typ, []string{def})
+		if err != nil {
+			return nil, false
+		}
+		return v, true
+	case reflect.String:
+		return def, true
+	case reflect.Slice:
+		ret := []any{}
+		for _, e := range splitDefault(def) {
+			v, ok := schemaDefault(typ.Elem(), e)
+			if !ok {
+				return nil, false
+			}
+			ret = append(ret, v)
+		}
+		return ret, true
+	}
+	return nil, false
+}
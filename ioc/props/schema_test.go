@@ -0,0 +1,152 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/anyvoxel/airmid/anvil/pointer"
+)
+
+type testSchemaEmbedded struct {
+	Region string `desc:"the region"`
+}
+
+type testSchemaNode struct {
+	testSchemaEmbedded
+
+	Name     string         `prop:"name:=root" desc:"the node name"`
+	Port     uint16         `prop:"port:=80"`
+	Timeout  *time.Duration `prop:"timeout:=1s"`
+	Labels   map[string]string
+	Children []testSchemaNode
+	Ignored  string `prop:"-"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	type testCase struct {
+		desp   string
+		typ    reflect.Type
+		def    *string
+		expect map[string]any
+	}
+	testCases := []testCase{
+		{
+			desp:   "bool with default",
+			typ:    reflect.TypeOf(true),
+			def:    pointer.StringPtr("true"),
+			expect: map[string]any{"type": "boolean", "default": true},
+		},
+		{
+			desp:   "int pointer with default",
+			typ:    reflect.TypeOf((*int)(nil)),
+			def:    pointer.StringPtr("8080"),
+			expect: map[string]any{"type": "integer", "default": 8080},
+		},
+		{
+			desp:   "float",
+			typ:    reflect.TypeOf(float64(0)),
+			expect: map[string]any{"type": "number"},
+		},
+		{
+			desp:   "duration",
+			typ:    reflect.TypeOf(time.Second),
+			def:    pointer.StringPtr("5s"),
+			expect: map[string]any{"type": "string", "pattern": durationPattern, "default": "5s"},
+		},
+		{
+			desp:   "registered type",
+			typ:    reflect.TypeOf(time.Time{}),
+			def:    pointer.StringPtr("2025-01-02"),
+			expect: map[string]any{"type": "string", "default": "2025-01-02"},
+		},
+		{
+			desp: "slice with default",
+			typ:  reflect.TypeOf([]int{}),
+			def:  pointer.StringPtr("1,2"),
+			expect: map[string]any{
+				"anyOf": []any{
+					map[string]any{"type": "array", "items": map[string]any{"type": "integer"}},
+					map[string]any{"type": "string"},
+				},
+				"default": []any{1, 2},
+			},
+		},
+		{
+			desp:   "invalid default",
+			typ:    reflect.TypeOf(0),
+			def:    pointer.StringPtr("x"),
+			expect: map[string]any{"type": "integer"},
+		},
+		{
+			desp:   "placeholder default",
+			typ:    reflect.TypeOf(""),
+			def:    pointer.StringPtr("${other}"),
+			expect: map[string]any{"type": "string"},
+		},
+		{
+			desp:   "value handle",
+			typ:    reflect.TypeOf((*Value[int])(nil)),
+			def:    pointer.StringPtr("1"),
+			expect: map[string]any{"type": "integer", "default": 1},
+		},
+		{
+			desp:   "interface",
+			typ:    reflect.TypeOf((*any)(nil)).Elem(),
+			expect: map[string]any{},
+		},
+		{
+			desp: "struct",
+			typ:  reflect.TypeOf(testSchemaNode{}),
+			expect: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"region": map[string]any{"type": "string", "description": "the region"},
+					"name":   map[string]any{"type": "string", "default": "root", "description": "the node name"},
+					"port":   map[string]any{"type": "integer", "minimum": 0, "default": uint16(80)},
+					"timeout": map[string]any{
+						"type": "string", "pattern": durationPattern, "default": "1s",
+					},
+					"labels": map[string]any{
+						"type":                 "object",
+						"additionalProperties": map[string]any{"type": "string"},
+					},
+					"children": map[string]any{
+						"anyOf": []any{
+							map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+							map[string]any{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(JSONSchema(tc.typ, tc.def)).To(Equal(tc.expect))
+		})
+	}
+}
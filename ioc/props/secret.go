@@ -0,0 +1,65 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"regexp"
+	"sync"
+)
+
+// RedactedValue is the placeholder of secret value in logs and outputs.
+const RedactedValue = "******"
+
+var (
+	secretKeyRegex = regexp.MustCompile(
+		`(?i)(password|passwd|secret|token|credential|private[._-]?key|api[._-]?key|encrypt[._-]?key)`)
+
+	secretKeysMu sync.RWMutex
+	secretKeys   = map[string]struct{}{}
+)
+
+// MarkSecretKeys mark the keys as secret, so the value of them will be redacted. The keys which
+// contain 'password', 'secret', 'token' etc. are secret by default.
+func MarkSecretKeys(keys ...string) {
+	secretKeysMu.Lock()
+	defer secretKeysMu.Unlock()
+
+	for _, k := range keys {
+		secretKeys[CanonicalKey(k)] = struct{}{}
+	}
+}
+
+// IsSecretKey return true if the value of key should be redacted, the marked keys are matched in
+// relaxed binding, e.g. 'TLS_CERT' is secret if 'tls.cert' is marked.
+func IsSecretKey(key string) bool {
+	secretKeysMu.RLock()
+	_, ok := secretKeys[CanonicalKey(key)]
+	secretKeysMu.RUnlock()
+
+	return ok || secretKeyRegex.MatchString(key)
+}
+
+// Redact return the RedactedValue if key is secret, otherwise return the value.
+func Redact(key string, value string) string {
+	if IsSecretKey(key) {
+		return RedactedValue
+	}
+	return value
+}
@@ -0,0 +1,593 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// Precedence is the precedence of property source, the value in the source with
+// higher precedence will overwrite the lower one.
+type Precedence int
+
+const (
+	// PrecedenceDefaults is the precedence for default properties.
+	PrecedenceDefaults Precedence = 0
+
+	// PrecedenceConfigFile is the precedence for base config file, e.g. application.yml.
+	PrecedenceConfigFile Precedence = 100
+
+	// PrecedenceProfileConfigFile is the precedence for profile config file, e.g. application-dev.yml.
+	PrecedenceProfileConfigFile Precedence = 200
+
+	// PrecedenceSecretDir is the precedence for mounted secret files, e.g. '/run/secrets/db/password'.
+	PrecedenceSecretDir Precedence = 230
+
+	// PrecedenceDotenv is the precedence for variables in dotenv files, e.g. '.env'.
+	PrecedenceDotenv Precedence = 250
+
+	// PrecedenceEnv is the precedence for environment variables.
+	PrecedenceEnv Precedence = 300
+
+	// PrecedenceFlags is the precedence for command line flags.
+	PrecedenceFlags Precedence = 400
+
+	// PrecedenceRuntime is the precedence for the properties set at runtime by Properties.Set.
+	PrecedenceRuntime Precedence = 500
+)
+
+const (
+	// RuntimePropertySourceName is the name of source which hold the properties set at runtime.
+	RuntimePropertySourceName string = "runtime"
+)
+
+// Origin describe where the property value come from.
+type Origin struct {
+	// Source is the name of property source, e.g. 'env', 'file config/application.yml'.
+	Source string
+
+	// Location is the location of value in source, e.g. 'AIRMID_PORT', 'line 12'.
+	Location string
+}
+
+// String return the readable origin, e.g. 'env AIRMID_PORT', 'file application-dev.yml line 12'.
+func (o Origin) String() string {
+	if o.Location == "" {
+		return o.Source
+	}
+	return o.Source + " " + o.Location
+}
+
+// LocationFunc return the location of flattened key in property source, e.g. 'AIRMID_PORT', 'line 12'.
+type LocationFunc func(key string) string
+
+type locationContextKey struct{}
+
+// ContextWithLocation return the context carrying fn, the PropertySource will record the location
+// of keys with fn when Set is called with this context.
+func ContextWithLocation(ctx context.Context, fn LocationFunc) context.Context {
+	return context.WithValue(ctx, locationContextKey{}, fn)
+}
+
+// LocationFromContext return the LocationFunc in ctx, or nil if not found.
+func LocationFromContext(ctx context.Context) LocationFunc {
+	fn, _ := ctx.Value(locationContextKey{}).(LocationFunc)
+	return fn
+}
+
+// NewPropertySource return the in-memory PropertySource impl, it is safe for concurrent use.
+func NewPropertySource(name string) PropertySource {
+	return &propertySourceImpl{
+		name:   name,
+		values: newSourceValues(propertiesImpl{}, map[string]string{}),
+	}
+}
+
+type propertySourceImpl struct {
+	name string
+
+	// mu protect the values, which is replaced by the updated copy on write, so the readers
+	// only hold the lock to load current values.
+	mu     sync.RWMutex
+	values *sourceValues
+}
+
+// sourceValues is the values of source, it must not be modified after stored, so the index of
+// props is built once for each version of values.
+type sourceValues struct {
+	props     propertiesImpl
+	locations map[string]string
+	index     *keyIndex
+}
+
+func newSourceValues(props propertiesImpl, locations map[string]string) *sourceValues {
+	return &sourceValues{
+		props:     props,
+		locations: locations,
+		index:     newKeyIndex(props),
+	}
+}
+
+func (s *propertySourceImpl) load() *sourceValues {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.values
+}
+
+// update apply fn on the copy of current values, and replace the values if fn succeed. The old
+// values is passed to fn for the relaxed lookup, since the index of copy isn't built yet.
+func (s *propertySourceImpl) update(fn func(old *sourceValues, v *sourceValues) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.values
+	v := newSourceValues(make(propertiesImpl, len(old.props)), make(map[string]string, len(old.locations)))
+	for k, val := range old.props {
+		v.props[k] = val
+	}
+	for k, loc := range old.locations {
+		v.locations[k] = loc
+	}
+
+	if err := fn(old, v); err != nil {
+		return err
+	}
+	s.values = v
+	return nil
+}
+
+func (s *propertySourceImpl) store() propertyStore {
+	return s.load().index
+}
+
+func (s *propertySourceImpl) Name() string {
+	return s.name
+}
+
+func (s *propertySourceImpl) Get(ctx context.Context, key string, opts ...GetOption) (any, error) {
+	return propertyReader{s.load().index}.Get(ctx, key, opts...)
+}
+
+func (s *propertySourceImpl) Set(ctx context.Context, key string, val any) error {
+	return s.SetAll(ctx, map[string]any{key: val})
+}
+
+// SetAll set the values with one update, so the readers see either none or all of them. The
+// replaced keys are described by keyIndex.replacedKeys.
+func (s *propertySourceImpl) SetAll(ctx context.Context, values map[string]any) error {
+	locate := LocationFromContext(ctx)
+	return s.update(func(old *sourceValues, v *sourceValues) error {
+		for key, val := range values {
+			for _, k := range old.index.replacedKeys(key, val) {
+				delete(v.props, k)
+				delete(v.locations, k)
+			}
+		}
+
+		for key, val := range values {
+			err := flattenValue(ctx, key, val, func(k string, value string) {
+				v.props[k] = value
+				delete(v.locations, k)
+				if locate == nil {
+					return
+				}
+				if loc := locate(k); loc != "" {
+					v.locations[k] = loc
+				}
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *propertySourceImpl) Keys(prefix string) []string {
+	return s.load().index.Keys(prefix)
+}
+
+func (s *propertySourceImpl) Has(key string) bool {
+	return s.load().index.Has(key)
+}
+
+func (s *propertySourceImpl) Remove(key string) {
+	_ = s.update(func(old *sourceValues, v *sourceValues) error {
+		for _, k := range old.index.Keys(key) {
+			delete(v.props, k)
+			delete(v.locations, k)
+		}
+		return nil
+	})
+}
+
+func (s *propertySourceImpl) Snapshot() map[string]string {
+	return s.load().index.Snapshot()
+}
+
+func (s *propertySourceImpl) Lookup(key string) (string, bool) {
+	return s.load().index.Lookup(key)
+}
+
+func (s *propertySourceImpl) Location(key string) string {
+	v := s.load()
+	if k, ok := v.index.findKey(key); ok {
+		key = k
+	}
+	if loc, ok := v.locations[key]; ok {
+		return loc
+	}
+
+	// The slice value is stored as indexed keys, the first element is used for the key.
+	return v.locations[key+"[0]"]
+}
+
+// NewProperties return the ConfigurableProperties impl, it contains a runtime source with
+// PrecedenceRuntime, which hold the properties set by Set. It is safe for concurrent use, and
+// each Get read a consistent view of sources.
+func NewProperties() ConfigurableProperties {
+	runtime := NewPropertySource(RuntimePropertySourceName)
+	p := &propertySourcesImpl{
+		runtime:        runtime,
+		consumedKeys:   map[string]struct{}{},
+		consumedSlices: map[string]struct{}{},
+	}
+	p.AddPropertySource(runtime, PrecedenceRuntime)
+	return p
+}
+
+type prioritizedSource struct {
+	source     PropertySource
+	precedence Precedence
+}
+
+type propertySourcesImpl struct {
+	// sources is ordered by priority, the first one is the highest. It is protected by mu and
+	// replaced by the updated copy on write, so the readers only hold the lock to load it.
+	sources []prioritizedSource
+	mu      sync.RWMutex
+	runtime PropertySource
+
+	// consumedKeys & consumedSlices is the canonical keys which are read as value & slice,
+	// the beans may be created concurrently so it is protected by consumedMu.
+	consumedKeys   map[string]struct{}
+	consumedSlices map[string]struct{}
+	consumedMu     sync.Mutex
+}
+
+func (p *propertySourcesImpl) loadSources() []prioritizedSource {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.sources
+}
+
+// view return the consistent view of current sources.
+func (p *propertySourcesImpl) view() *sourcesView {
+	sources := p.loadSources()
+	stores := make([]propertyStore, 0, len(sources))
+	for _, s := range sources {
+		stores = append(stores, sourceStore(s.source))
+	}
+	return &sourcesView{p: p, sources: sources, stores: stores}
+}
+
+func (p *propertySourcesImpl) Get(ctx context.Context, key string, opts ...GetOption) (any, error) {
+	return propertyReader{p.view()}.Get(ctx, key, opts...)
+}
+
+func (p *propertySourcesImpl) Set(ctx context.Context, key string, val any) error {
+	return p.runtime.Set(ctx, key, val)
+}
+
+func (p *propertySourcesImpl) SetAll(ctx context.Context, values map[string]any) error {
+	return p.runtime.SetAll(ctx, values)
+}
+
+// Keys return the keys in all sources.
+func (p *propertySourcesImpl) Keys(prefix string) []string {
+	keys := map[string]struct{}{}
+	for _, s := range p.loadSources() {
+		for _, k := range s.source.Keys(prefix) {
+			keys[k] = struct{}{}
+		}
+	}
+
+	ret := make([]string, 0, len(keys))
+	for k := range keys {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (p *propertySourcesImpl) Has(key string) bool {
+	for _, s := range p.loadSources() {
+		if s.source.Has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove the key from the runtime source which hold the values of Set, so the value in other sources
+// is visible again. The other sources are unchanged, since they are reloaded from their origin.
+func (p *propertySourcesImpl) Remove(key string) {
+	p.runtime.Remove(key)
+}
+
+// Snapshot return the value of all keys, which is read from the source with highest precedence.
+func (p *propertySourcesImpl) Snapshot() map[string]string {
+	view := p.view()
+	reader := propertyReader{view}
+	ret := map[string]string{}
+	for i, s := range view.sources {
+		var store propertiesImpl
+		if x, ok := view.stores[i].(*keyIndex); ok {
+			store = x.props
+		} else {
+			store = (&lazySourceStore{source: s.source}).snapshot()
+		}
+
+		for k, v := range store {
+			if _, ok := ret[k]; ok {
+				continue
+			}
+			if rv, err := reader.resolve(v); err == nil {
+				v = rv
+			}
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+func (p *propertySourcesImpl) AddPropertySource(source PropertySource, precedence Precedence) {
+	p.updateSources(func(sources []prioritizedSource) []prioritizedSource {
+		sources = removeSource(sources, source.Name())
+		idx := sort.Search(len(sources), func(i int) bool {
+			return sources[i].precedence <= precedence
+		})
+		sources = append(sources, prioritizedSource{})
+		copy(sources[idx+1:], sources[idx:])
+		sources[idx] = prioritizedSource{
+			source:     source,
+			precedence: precedence,
+		}
+		return sources
+	})
+}
+
+func (p *propertySourcesImpl) RemovePropertySource(name string) {
+	p.updateSources(func(sources []prioritizedSource) []prioritizedSource {
+		return removeSource(sources, name)
+	})
+}
+
+// updateSources apply fn on the copy of current sources, and replace the sources with result.
+func (p *propertySourcesImpl) updateSources(fn func(sources []prioritizedSource) []prioritizedSource) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sources = fn(append([]prioritizedSource{}, p.sources...))
+}
+
+func removeSource(sources []prioritizedSource, name string) []prioritizedSource {
+	for i, s := range sources {
+		if s.source.Name() == name {
+			return append(sources[:i], sources[i+1:]...)
+		}
+	}
+	return sources
+}
+
+func (p *propertySourcesImpl) PropertySources() []PropertySource {
+	sources := p.loadSources()
+	ret := make([]PropertySource, 0, len(sources))
+	for _, s := range sources {
+		ret = append(ret, s.source)
+	}
+	return ret
+}
+
+func (p *propertySourcesImpl) Origin(_ context.Context, key string) (Origin, error) {
+	view := p.view()
+	for i, s := range view.sources {
+		if view.stores[i].hasKey(key) {
+			return Origin{
+				Source:   s.source.Name(),
+				Location: s.source.Location(key),
+			}, nil
+		}
+	}
+
+	return Origin{}, xerrors.WrapNotFound("property with key='%v' not found", key)
+}
+
+var indexedKeyRegex = regexp.MustCompile(`^(.*)\[[0-9]+\]$`)
+
+func (p *propertySourcesImpl) IsConsumed(key string) bool {
+	key = CanonicalKey(key)
+	p.consumedMu.Lock()
+	defer p.consumedMu.Unlock()
+
+	if _, ok := p.consumedKeys[key]; ok {
+		return true
+	}
+	if m := indexedKeyRegex.FindStringSubmatch(key); m != nil {
+		_, ok := p.consumedSlices[m[1]]
+		return ok
+	}
+	return false
+}
+
+func (p *propertySourcesImpl) consume(key string, slice bool) {
+	key = CanonicalKey(key)
+	p.consumedMu.Lock()
+	defer p.consumedMu.Unlock()
+
+	p.consumedKeys[key] = struct{}{}
+	if slice {
+		p.consumedSlices[key] = struct{}{}
+	}
+}
+
+// sourcesView is the stores of sources at a moment, the value read from view is consistent
+// even if the sources are updated concurrently.
+type sourcesView struct {
+	p       *propertySourcesImpl
+	sources []prioritizedSource
+	stores  []propertyStore
+}
+
+func (v *sourcesView) doGet(key string) (string, error) {
+	v.p.consume(key, false)
+	for _, s := range v.stores {
+		val, err := s.doGet(key)
+		if err == nil || !xerrors.IsNotFound(err) {
+			return val, err
+		}
+	}
+
+	return "", xerrors.WrapNotFound("property with key='%v' not found", key)
+}
+
+// doGetSlice return the slice from the first source which contains the key, the elements
+// will not be merged across sources.
+func (v *sourcesView) doGetSlice(key string) ([]string, error) {
+	v.p.consume(key, true)
+	for _, s := range v.stores {
+		val, err := s.doGetSlice(key)
+		if err == nil || !xerrors.IsNotFound(err) {
+			return val, err
+		}
+	}
+
+	return nil, xerrors.WrapNotFound("property slice with key='%v' not found", key)
+}
+
+func (v *sourcesView) hasKey(key string) bool {
+	for _, s := range v.stores {
+		if s.hasKey(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *sourcesView) childNames(prefix string) []string {
+	names := map[string]struct{}{}
+	for _, s := range v.stores {
+		for _, name := range s.childNames(prefix) {
+			names[name] = struct{}{}
+		}
+	}
+
+	ret := make([]string, 0, len(names))
+	for k := range names {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// childIndexes return the indexes from the first source which contains the indexed keys,
+// so the slice in higher source will replace the whole slice in lower one.
+func (v *sourcesView) childIndexes(prefix string) ([]int64, error) {
+	for _, s := range v.stores {
+		indexes, err := s.childIndexes(prefix)
+		if err != nil || len(indexes) > 0 {
+			return indexes, err
+		}
+	}
+	return []int64{}, nil
+}
+
+// sourceStore return the current store of source, the custom source will be accessed with
+// a snapshot of its keys.
+func sourceStore(source PropertySource) propertyStore {
+	if s, ok := source.(interface{ store() propertyStore }); ok {
+		return s.store()
+	}
+	return &lazySourceStore{source: source}
+}
+
+type lazySourceStore struct {
+	source PropertySource
+}
+
+func (s *lazySourceStore) snapshot() propertiesImpl {
+	ret := propertiesImpl{}
+	for _, k := range s.source.Keys("") {
+		if v, ok := s.source.Lookup(k); ok {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+func (s *lazySourceStore) doGet(key string) (string, error) {
+	if v, ok := s.source.Lookup(key); ok {
+		return v, nil
+	}
+	return s.snapshot().doGet(key)
+}
+
+func (s *lazySourceStore) doGetSlice(key string) ([]string, error) {
+	return s.snapshot().doGetSlice(key)
+}
+
+func (s *lazySourceStore) hasKey(key string) bool {
+	if _, ok := s.source.Lookup(key); ok {
+		return true
+	}
+	return s.snapshot().hasKey(key)
+}
+
+func (s *lazySourceStore) childNames(prefix string) []string {
+	return s.snapshot().childNames(prefix)
+}
+
+func (s *lazySourceStore) childIndexes(prefix string) ([]int64, error) {
+	return s.snapshot().childIndexes(prefix)
+}
+
+// ChangedKeys return the sorted keys which are added, removed or updated from before to after.
+func ChangedKeys(before map[string]string, after map[string]string) []string {
+	ret := []string{}
+	for k, v := range before {
+		if nv, ok := after[k]; !ok || nv != v {
+			ret = append(ret, k)
+		}
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			ret = append(ret, k)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,451 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// testMapSource is the custom PropertySource, which is not backed by propertiesImpl.
+type testMapSource struct {
+	Properties
+	name   string
+	values map[string]string
+}
+
+func (s *testMapSource) Name() string {
+	return s.name
+}
+
+func (s *testMapSource) Keys(prefix string) []string {
+	return propertiesImpl(s.values).Keys(prefix)
+}
+
+func (s *testMapSource) Lookup(key string) (string, bool) {
+	v, ok := s.values[key]
+	return v, ok
+}
+
+func (*testMapSource) Location(string) string {
+	return ""
+}
+
+func newTestSource(t *testing.T, name string, values map[string]any) PropertySource {
+	s := NewPropertySource(name)
+	ctx := ContextWithLocation(context.Background(), func(key string) string {
+		return "at " + key
+	})
+	for k, v := range values {
+		if err := s.Set(ctx, k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestPropertySource(t *testing.T) {
+	g := NewWithT(t)
+	s := NewPropertySource("test")
+	g.Expect(s.Name()).To(Equal("test"))
+
+	err := s.Set(context.Background(), "a", 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(s.Location("a")).To(BeEmpty())
+
+	ctx := ContextWithLocation(context.Background(), func(key string) string {
+		return fmt.Sprintf("line %v", len(key))
+	})
+	err = s.Set(ctx, "b", map[string]any{"c": []int{1, 2}})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(s.Keys("")).To(Equal([]string{"a", "b.c[0]", "b.c[1]"}))
+	g.Expect(s.Location("b.c[1]")).To(Equal("line 6"))
+	g.Expect(s.Location("b.c")).To(Equal("line 6"))
+
+	v, ok := s.Lookup("b.c[0]")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(v).To(Equal("1"))
+
+	// The key is looked up in relaxed binding
+	v, ok = s.Lookup("B.C[1]")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(v).To(Equal("2"))
+	_, ok = s.Lookup("b.c[2]")
+	g.Expect(ok).To(BeFalse())
+
+	// The location will be cleared when the key is overwritten without location
+	err = s.Set(context.Background(), "b.c[0]", 3)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(s.Location("b.c[0]")).To(BeEmpty())
+
+	var target []int
+	_, err = s.Get(context.Background(), "b.c", WithTarget(&target))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(target).To(Equal([]int{3, 2}))
+}
+
+func TestPropertySourcesPrecedence(t *testing.T) {
+	g := NewWithT(t)
+	p := NewProperties()
+	p.AddPropertySource(newTestSource(t, "env", map[string]any{
+		"port": 8081,
+	}), PrecedenceEnv)
+	p.AddPropertySource(newTestSource(t, "defaults", map[string]any{
+		"port":    80,
+		"host":    "localhost",
+		"address": "${host}:${port}",
+	}), PrecedenceDefaults)
+	p.AddPropertySource(newTestSource(t, "file a.yml", map[string]any{
+		"port": 8080,
+		"host": "a",
+	}), PrecedenceConfigFile)
+	p.AddPropertySource(newTestSource(t, "file b.yml", map[string]any{
+		"host": "b",
+	}), PrecedenceConfigFile)
+
+	names := []string{}
+	for _, s := range p.PropertySources() {
+		names = append(names, s.Name())
+	}
+	g.Expect(names).To(Equal([]string{"runtime", "env", "file b.yml", "file a.yml", "defaults"}))
+
+	v, err := p.Get(context.Background(), "address")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(v).To(Equal("b:8081"))
+
+	origin, err := p.Origin(context.Background(), "port")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(origin).To(Equal(Origin{Source: "env", Location: "at port"}))
+	g.Expect(origin.String()).To(Equal("env at port"))
+
+	err = p.Set(context.Background(), "port", 9090)
+	g.Expect(err).ToNot(HaveOccurred())
+	v, err = p.Get(context.Background(), "port")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(v).To(Equal("9090"))
+	origin, err = p.Origin(context.Background(), "port")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(origin.String()).To(Equal("runtime"))
+
+	// The source with same name will be replaced
+	p.AddPropertySource(newTestSource(t, "file b.yml", map[string]any{}), PrecedenceConfigFile)
+	v, err = p.Get(context.Background(), "host")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(v).To(Equal("a"))
+
+	p.RemovePropertySource("file a.yml")
+	v, err = p.Get(context.Background(), "host")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(v).To(Equal("localhost"))
+
+	_, err = p.Origin(context.Background(), "x")
+	g.Expect(xerrors.IsNotFound(err)).To(BeTrue())
+
+	_, err = p.Get(context.Background(), "x")
+	g.Expect(xerrors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestPropertySourcesBind(t *testing.T) {
+	type server struct {
+		Host string
+		Port int
+	}
+	type config struct {
+		Name    string
+		Servers []server
+		Tags    []string
+		Labels  map[string]string
+	}
+
+	g := NewWithT(t)
+	p := NewProperties()
+	p.AddPropertySource(newTestSource(t, "file", map[string]any{
+		"app": map[string]any{
+			"name": "a",
+			"servers": []map[string]any{
+				{"host": "h1", "port": 1},
+				{"host": "h2", "port": 2},
+			},
+			"tags": []string{"t1", "t2"},
+			"labels": map[string]string{
+				"k1": "v1",
+			},
+		},
+	}), PrecedenceConfigFile)
+	p.AddPropertySource(&testMapSource{
+		name: "custom",
+		values: map[string]string{
+			"app.servers[0].host": "h3",
+			"app.tags[0]":         "t3",
+			"app.labels.k2":       "v2",
+		},
+	}, PrecedenceEnv)
+
+	c := config{}
+	err := Bind(context.Background(), p, "app", &c)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c).To(Equal(config{
+		Name: "a",
+		// The slice in higher source will replace the whole slice in lower one
+		Servers: []server{{Host: "h3", Port: 1}},
+		Tags:    []string{"t3"},
+		Labels: map[string]string{
+			"k1": "v1",
+			"k2": "v2",
+		},
+	}))
+
+	origin, err := p.Origin(context.Background(), "app.tags")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(origin.String()).To(Equal("custom"))
+
+	origin, err = p.Origin(context.Background(), "app.labels.k1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(origin.String()).To(Equal("file at app.labels.k1"))
+}
+
+func TestPropertySourcesIsConsumed(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	p := NewProperties()
+	p.AddPropertySource(newTestSource(t, "file a.yml", map[string]any{
+		"host":  "${db.host}",
+		"db":    map[string]any{"host": "h", "hostt": "x"},
+		"ports": []any{"80", "81"},
+		"other": "o",
+	}), PrecedenceConfigFile)
+
+	_, err := p.Get(ctx, "host")
+	g.Expect(err).ToNot(HaveOccurred())
+	var ports []int
+	_, err = p.Get(ctx, "ports", WithTarget(&ports))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = p.Get(ctx, "missing", WithDefault("d"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	for key, expect := range map[string]bool{
+		"host":     true,
+		"db.host":  true,
+		"db.hostt": false,
+		"ports[0]": true,
+		"ports[1]": true,
+		"other":    false,
+		"missing":  true,
+	} {
+		g.Expect(p.IsConsumed(key)).To(Equal(expect), key)
+	}
+}
+
+func TestPropertySourceSetAll(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	s := NewPropertySource("s")
+	g.Expect(s.Set(ctx, "a", "1")).To(Succeed())
+
+	// None of values is set if any of them failed
+	err := s.SetAll(ctx, map[string]any{"b": "2", "c": struct{}{}})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(s.Keys("")).To(Equal([]string{"a"}))
+
+	ctx = ContextWithLocation(ctx, func(key string) string {
+		return "at " + key
+	})
+	g.Expect(s.SetAll(ctx, map[string]any{"b": "2", "c": []string{"x", "y"}})).To(Succeed())
+	g.Expect(s.Snapshot()).To(Equal(map[string]string{"a": "1", "b": "2", "c[0]": "x", "c[1]": "y"}))
+	g.Expect(s.Location("c")).To(Equal("at c[0]"))
+
+	p := NewProperties()
+	g.Expect(p.SetAll(ctx, map[string]any{"a": "1", "b": "2"})).To(Succeed())
+	g.Expect(p.Snapshot()).To(Equal(map[string]string{"a": "1", "b": "2"}))
+}
+
+func TestPropertySourceSetAllReloadShrink(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	s := NewPropertySource("s")
+	g.Expect(s.SetAll(ctx, map[string]any{
+		"server": map[string]any{
+			"hosts":  []string{"a", "b"},
+			"labels": map[string]string{"x": "1", "y": "2"},
+		},
+		"port": 80,
+	})).To(Succeed())
+
+	// The reload replace the whole subtree of each key, so the removed children are deleted
+	g.Expect(s.SetAll(ctx, map[string]any{
+		"server": map[string]any{
+			"hosts":  []string{"c"},
+			"labels": map[string]string{"x": "3"},
+		},
+	})).To(Succeed())
+	g.Expect(s.Snapshot()).To(Equal(map[string]string{
+		"server.hosts[0]": "c",
+		"server.labels.x": "3",
+		"port":            "80",
+	}))
+
+	var labels map[string]string
+	_, err := s.Get(ctx, "server.labels", WithTarget(&labels))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(labels).To(Equal(map[string]string{"x": "3"}))
+
+	// The failed reload keep the current values
+	g.Expect(s.SetAll(ctx, map[string]any{"server": map[string]any{"hosts": struct{}{}}})).ToNot(Succeed())
+	g.Expect(s.Keys("server")).To(Equal([]string{"server.hosts[0]", "server.labels.x"}))
+}
+
+func TestPropertySetShrink(t *testing.T) {
+	type testCase struct {
+		desp string
+		p    interface {
+			Set(ctx context.Context, key string, val any) error
+			Snapshot() map[string]string
+		}
+	}
+	testCases := []testCase{
+		{desp: "source", p: NewPropertySource("s")},
+		{desp: "properties", p: NewProperties()},
+		{desp: "map", p: propertiesImpl{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			g.Expect(tc.p.Set(ctx, "hostsx", "x")).To(Succeed())
+			g.Expect(tc.p.Set(ctx, "hosts", []string{"a", "b", "c"})).To(Succeed())
+			g.Expect(tc.p.Set(ctx, "hosts", []string{"a"})).To(Succeed())
+			g.Expect(tc.p.Snapshot()).To(Equal(map[string]string{"hostsx": "x", "hosts[0]": "a"}))
+
+			var hosts []string
+			_, err := tc.p.(Properties).Get(ctx, "hosts", WithTarget(&hosts))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(hosts).To(Equal([]string{"a"}))
+
+			// The scalar value only overwrite the key itself, and the map value replace the subtree
+			g.Expect(tc.p.Set(ctx, "hosts", map[string]any{"a": map[string]any{"b": 1}})).To(Succeed())
+			g.Expect(tc.p.Set(ctx, "hosts", "a")).To(Succeed())
+			g.Expect(tc.p.Snapshot()).To(Equal(map[string]string{"hostsx": "x", "hosts": "a", "hosts.a.b": "1"}))
+			g.Expect(tc.p.Set(ctx, "hosts", map[string]any{"c": 2})).To(Succeed())
+			g.Expect(tc.p.Snapshot()).To(Equal(map[string]string{"hostsx": "x", "hosts.c": "2"}))
+		})
+	}
+}
+
+func TestPropertySetScalarParent(t *testing.T) {
+	type testCase struct {
+		desp string
+		keys []string
+	}
+	testCases := []testCase{
+		{desp: "parent first", keys: []string{"db", "db.host"}},
+		{desp: "child first", keys: []string{"db.host", "db"}},
+		{desp: "relaxed parent", keys: []string{"db.host", "DB"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			s := NewPropertySource("s")
+			p := propertiesImpl{}
+			for _, k := range tc.keys {
+				g.Expect(s.Set(ctx, k, k)).To(Succeed())
+				g.Expect(p.Set(ctx, k, k)).To(Succeed())
+			}
+			g.Expect(s.Keys("")).To(ConsistOf(tc.keys))
+			g.Expect(p.Keys("")).To(ConsistOf(tc.keys))
+			g.Expect(s.Get(ctx, "db.host")).To(Equal("db.host"))
+
+			// The relaxed equal key is replaced by the scalar value
+			g.Expect(s.Set(ctx, "DB_HOST", "h")).To(Succeed())
+			g.Expect(s.Keys("db.host")).To(Equal([]string{"DB_HOST"}))
+		})
+	}
+}
+
+func TestPropertiesConcurrentAccess(t *testing.T) {
+	type testPair struct {
+		A int
+		B int
+	}
+
+	g := NewWithT(t)
+	ctx := context.Background()
+	p := NewProperties()
+	g.Expect(p.SetAll(ctx, map[string]any{"pair.a": 0, "pair.b": 0})).To(Succeed())
+
+	const n = 200
+	wg := sync.WaitGroup{}
+	errCh := make(chan error, 8*n)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= n; i++ {
+			if err := p.SetAll(ctx, map[string]any{"pair.a": i, "pair.b": i}); err != nil {
+				errCh <- err
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= n; i++ {
+			// The source is replaced with the same values, the readers see either of them
+			s := NewPropertySource("file")
+			if err := s.SetAll(ctx, map[string]any{"pair": map[string]any{"a": -i, "b": -i}}); err != nil {
+				errCh <- err
+			}
+			p.AddPropertySource(s, PrecedenceConfigFile)
+			p.Remove("other")
+			_ = p.Set(ctx, "other", i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			var pair testPair
+			if err := Bind(ctx, p, "pair", &pair); err != nil {
+				errCh <- err
+				continue
+			}
+			if pair.A != pair.B {
+				errCh <- fmt.Errorf("inconsistent pair %+v", pair)
+			}
+			_ = p.Has("other")
+			_ = p.Keys("")
+			_ = p.Snapshot()
+			_ = p.IsConsumed("pair.a")
+			_, _ = p.Origin(ctx, "pair.a")
+		}
+	}()
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(GetAs[int](ctx, p, "pair.a")).To(Equal(n))
+}
@@ -26,11 +26,73 @@ import (
 
 // Properties hold the configurable properties name & value.
 type Properties interface {
-	// Get return the value for key, the key is case-sensitive.
+	// Get return the value for key, the key is matched in relaxed binding if it isn't found exactly,
+	// e.g. 'a.b-c', 'a.b_c', 'a.bC' and 'A_B_C' are matched with each other, see CanonicalKey.
 	Get(ctx context.Context, key string, opts ...GetOption) (any, error)
 
-	// Set the value for key, it will overwrite the old value if key is already exists.
+	// Set the value for key, the map or slice val is flattened into nested or indexed keys (e.g.
+	// 'key.sub', 'key[0]') and replace the whole subtree of key, so the shrunk one won't keep stale
+	// children. The scalar val only overwrite the old value of key, and its children are kept.
 	// The val will been transform to string to store with the container, so when
 	//   user try to get the val of key, the string returned.
 	Set(ctx context.Context, key string, val any) error
+
+	// SetAll set the values of keys as Set in one batch, it's atomic that none of values is set
+	// if any error occurred, and the readers never see part of them for the concurrent-safe impl.
+	SetAll(ctx context.Context, values map[string]any) error
+
+	// Keys return the sorted flattened keys under prefix, include the prefix itself, its indexed
+	// keys (e.g. 'prefix[0]') and nested keys (e.g. 'prefix.sub'), all keys are returned if prefix
+	// is empty. The prefix is matched in relaxed binding.
+	Keys(prefix string) []string
+
+	// Has return true if the key or any of its indexed or nested keys exists.
+	Has(key string) bool
+
+	// Remove the key and its indexed and nested keys, which are the Keys of key. The Properties
+	// only remove the key set by Set, so the value in other property sources is visible again.
+	Remove(key string)
+
+	// Snapshot return the value of all keys, the placeholders are resolved if possible.
+	Snapshot() map[string]string
+}
+
+// PropertySource is the named properties, e.g. flags, env or config file. The keys
+// of source are flattened, e.g. 'db.host', 'servers[0]'.
+type PropertySource interface {
+	Properties
+
+	// Name return the unique name of source, e.g. 'env', 'file config/application.yml'.
+	Name() string
+
+	// Lookup return the raw value of flattened key, the key is matched in relaxed binding,
+	// e.g. 'db.maxPool' for 'db.max-pool', and the exact key is preferred.
+	Lookup(key string) (string, bool)
+
+	// Location return the location of key in source, e.g. 'AIRMID_PORT', 'line 12'.
+	// It return empty string if the location is unknown.
+	Location(key string) string
+}
+
+// ConfigurableProperties is the Properties composed by ordered property sources, the value of
+// key is resolved from the source with the highest precedence.
+type ConfigurableProperties interface {
+	Properties
+
+	// AddPropertySource add the source with precedence, the source with same name will be replaced.
+	// For the sources with same precedence, the later added one take higher priority.
+	AddPropertySource(source PropertySource, precedence Precedence)
+
+	// RemovePropertySource remove the source with name.
+	RemovePropertySource(name string)
+
+	// PropertySources return the sources ordered by priority, the first one is the highest.
+	PropertySources() []PropertySource
+
+	// Origin return where the value of key come from.
+	Origin(ctx context.Context, key string) (Origin, error)
+
+	// IsConsumed return true if the key has been read by Get, include the placeholder reference
+	// and the element of slice (e.g. 'hosts[0]' is consumed when 'hosts' is read as slice).
+	IsConsumed(key string) bool
 }
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+)
+
+// PropertyHandle is the field type which will be attached to properties when injected,
+// instead of being set with the property value, e.g. Value[T].
+type PropertyHandle interface {
+	// AttachProperties attach the handle to the key of properties.
+	AttachProperties(p Properties, key string, opts ...GetOption)
+}
+
+// Value is the handle of property, which always return the current value of key.
+// It can be injected into bean field with `airmid:"value:${key:=default}"`, so the
+// bean will see the refreshed value without re-injection.
+type Value[T any] struct {
+	p    Properties
+	key  string
+	opts []GetOption
+}
+
+// NewValue return the handle of key in properties, the opts should not specify target or type.
+func NewValue[T any](p Properties, key string, opts ...GetOption) *Value[T] {
+	v := &Value[T]{}
+	v.AttachProperties(p, key, opts...)
+	return v
+}
+
+// AttachProperties implement the PropertyHandle.AttachProperties.
+func (v *Value[T]) AttachProperties(p Properties, key string, opts ...GetOption) {
+	v.p = p
+	v.key = key
+	v.opts = opts
+}
+
+// Key return the property key of handle.
+func (v *Value[T]) Key() string {
+	return v.key
+}
+
+// Get return the current value of key.
+func (v *Value[T]) Get(ctx context.Context) (T, error) {
+	var ret T
+	if v.p == nil {
+		return ret, xerrors.Errorf("Value of key '%v' is not attached to properties", v.key)
+	}
+
+	opts := append(append([]GetOption{}, v.opts...), WithTarget(&ret))
+	if _, err := v.p.Get(ctx, v.key, opts...); err != nil {
+		return ret, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package props
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestValue(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	p := NewProperties()
+
+	v := NewValue[time.Duration](p, "timeout", WithDefault("1s"))
+	g.Expect(v.Key()).To(Equal("timeout"))
+	g.Expect(v.Get(ctx)).To(Equal(time.Second))
+
+	g.Expect(p.Set(ctx, "timeout", "3s")).To(Succeed())
+	g.Expect(v.Get(ctx)).To(Equal(3 * time.Second))
+
+	g.Expect(p.Set(ctx, "timeout", "x")).To(Succeed())
+	_, err := v.Get(ctx)
+	g.Expect(err).To(HaveOccurred())
+
+	var unattached Value[string]
+	_, err = unattached.Get(ctx)
+	g.Expect(err).To(MatchError("Value of key '' is not attached to properties"))
+}
+
+func TestSnapshotAndChangedKeys(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	p := NewProperties()
+	p.AddPropertySource(newTestSource(t, "file", map[string]any{
+		"host":    "localhost",
+		"port":    80,
+		"address": "${host}:${port}",
+		"bad":     "${x}",
+	}), PrecedenceConfigFile)
+
+	old := p.Snapshot()
+	g.Expect(old).To(Equal(map[string]string{
+		"host":    "localhost",
+		"port":    "80",
+		"address": "localhost:80",
+		"bad":     "${x}",
+	}))
+
+	g.Expect(p.Set(ctx, "port", 8080)).To(Succeed())
+	g.Expect(p.Set(ctx, "tags", []int{1})).To(Succeed())
+	p.AddPropertySource(newTestSource(t, "file", map[string]any{
+		"host": "localhost",
+		"port": 80,
+	}), PrecedenceConfigFile)
+
+	g.Expect(ChangedKeys(old, p.Snapshot())).To(Equal([]string{
+		"address", "bad", "port", "tags[0]",
+	}))
+}
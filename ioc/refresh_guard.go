@@ -0,0 +1,66 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ioc
+
+import (
+	"reflect"
+	"sync"
+)
+
+// refreshLockerType is the reflect.Type of RefreshLocker.
+var refreshLockerType = reflect.TypeOf((*RefreshLocker)(nil)).Elem()
+
+// RefreshLocker is implemented by the bean which has refreshable property fields, the fields of
+// bean are re-injected under the write lock, so they must be read under the read lock.
+type RefreshLocker interface {
+	RefreshLocker() *sync.RWMutex
+}
+
+// RefreshGuard implement RefreshLocker, it should be embedded into the bean which has refreshable
+// property fields, e.g.
+//
+//	type server struct {
+//		ioc.RefreshGuard
+//		timeout time.Duration `airmid:"value:${timeout:=1s},refresh"`
+//	}
+//
+//	func (s *server) Timeout() time.Duration {
+//		s.RLock()
+//		defer s.RUnlock()
+//		return s.timeout
+//	}
+type RefreshGuard struct {
+	mu sync.RWMutex
+}
+
+// RefreshLocker implement RefreshLocker.
+func (g *RefreshGuard) RefreshLocker() *sync.RWMutex {
+	return &g.mu
+}
+
+// RLock lock the refreshable fields for reading.
+func (g *RefreshGuard) RLock() {
+	g.mu.RLock()
+}
+
+// RUnlock undo a single RLock call.
+func (g *RefreshGuard) RUnlock() {
+	g.mu.RUnlock()
+}
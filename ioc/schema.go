@@ -0,0 +1,114 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ioc
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+const (
+	// JSONSchemaDraft is the JSON Schema dialect of generated schema.
+	JSONSchemaDraft string = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// JSONSchema return the JSON Schema of properties consumed by the bean definitions in registry.
+// The dotted keys are nested as objects (e.g. 'db.host' as {"db": {"host": ...}}), so it can be
+// used to validate & complete the config files, e.g. application.yml.
+func JSONSchema(registry BeanDefinitionRegistry) ([]byte, error) {
+	root := newObjectSchema()
+	root["$schema"] = JSONSchemaDraft
+	for _, d := range PropertyDescriptors(registry) {
+		s := props.JSONSchema(d.Typ, d.Default)
+		if d.Description != "" {
+			s["description"] = d.Description
+		}
+
+		node := root
+		names := strings.Split(d.Key, ".")
+		for _, name := range names[:len(names)-1] {
+			node = childObjectSchema(node, name)
+		}
+
+		properties := node["properties"].(map[string]any)
+		if exist, ok := properties[names[len(names)-1]].(map[string]any); ok {
+			// The key is consumed by multiple fields or is the prefix of other keys,
+			// the first schema take precedence.
+			mergeSchema(exist, s)
+			continue
+		}
+		properties[names[len(names)-1]] = s
+	}
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+func newObjectSchema() map[string]any {
+	return map[string]any{
+		"type":       "object",
+		"properties": map[string]any{},
+	}
+}
+
+// childObjectSchema return the object schema of child name, it will be created if not exists.
+func childObjectSchema(node map[string]any, name string) map[string]any {
+	properties := node["properties"].(map[string]any)
+	child, ok := properties[name].(map[string]any)
+	if !ok {
+		child = newObjectSchema()
+		properties[name] = child
+		return child
+	}
+
+	if _, ok := child["properties"].(map[string]any); !ok {
+		// The child is described as non-object, e.g. the map or scalar, we must
+		// add the properties to hold the nested keys.
+		child["properties"] = map[string]any{}
+	}
+	return child
+}
+
+// mergeSchema merge the src into dst, the existing attributes in dst are kept.
+func mergeSchema(dst map[string]any, src map[string]any) {
+	for k, v := range src {
+		exist, ok := dst[k]
+		if !ok {
+			dst[k] = v
+			continue
+		}
+
+		dp, ok1 := exist.(map[string]any)
+		sp, ok2 := v.(map[string]any)
+		if k != "properties" || !ok1 || !ok2 {
+			continue
+		}
+		for name, ps := range sp {
+			if ds, ok := dp[name].(map[string]any); ok {
+				if pss, ok := ps.(map[string]any); ok {
+					mergeSchema(ds, pss)
+				}
+				continue
+			}
+			dp[name] = ps
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ioc
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type testSchemaDBConfig struct {
+	Host string `prop:"host:=localhost"`
+}
+
+type testSchemaBean1 struct {
+	port  int                 `airmid:"value:${server.port:=8080}" desc:"the listen port"`
+	debug bool                `airmid:"value:${debug:=false}"`
+	db    *testSchemaDBConfig `airmid:"config:db" desc:"the database"`
+}
+
+type testSchemaBean2 struct {
+	port     string            `airmid:"value:${server.port}"`
+	poolSize int               `airmid:"value:${db.pool.size:=10}"`
+	labels   map[string]string `airmid:"value:${labels}"`
+	team     string            `airmid:"value:${labels.team:=infra}"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	g := NewWithT(t)
+	r := NewBeanDefinitionRegistry()
+	g.Expect(r.RegisterBeanDefinition("b1", MustNewBeanDefinition(
+		reflect.TypeOf((*testSchemaBean1)(nil))))).To(Succeed())
+	g.Expect(r.RegisterBeanDefinition("b2", MustNewBeanDefinition(
+		reflect.TypeOf((*testSchemaBean2)(nil))))).To(Succeed())
+
+	data, err := JSONSchema(r)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var schema map[string]any
+	g.Expect(json.Unmarshal(data, &schema)).To(Succeed())
+	g.Expect(schema).To(Equal(map[string]any{
+		"$schema": JSONSchemaDraft,
+		"type":    "object",
+		"properties": map[string]any{
+			"debug": map[string]any{"type": "boolean", "default": false},
+			"db": map[string]any{
+				"type":        "object",
+				"description": "the database",
+				"properties": map[string]any{
+					"host": map[string]any{"type": "string", "default": "localhost"},
+					"pool": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"size": map[string]any{"type": "integer", "default": float64(10)},
+						},
+					},
+				},
+			},
+			"labels": map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "string"},
+				"properties": map[string]any{
+					"team": map[string]any{"type": "string", "default": "infra"},
+				},
+			},
+			"server": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"port": map[string]any{"type": "integer", "default": float64(8080), "description": "the listen port"},
+				},
+			},
+		},
+	}))
+}
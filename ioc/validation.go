@@ -0,0 +1,327 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ioc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anyvoxel/airmid/anvil/xerrors"
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+const (
+	// ValidateTagName is the tag name for property validation rules, e.g. `validate:"min=1,max=65535"`.
+	ValidateTagName string = "validate"
+
+	// RequiredRule require the key to be set in properties, the default value is not accepted.
+	RequiredRule string = "required"
+
+	// NonemptyRule require the string, slice or map value to be non-empty.
+	NonemptyRule string = "nonempty"
+
+	// MinRule require the number or duration to be at least the argument,
+	// or the length of string, slice or map to be at least the argument.
+	MinRule string = "min"
+
+	// MaxRule require the number or duration to be at most the argument,
+	// or the length of string, slice or map to be at most the argument.
+	MaxRule string = "max"
+
+	// OneofRule require the value to be one of the '|' separated argument, e.g. 'oneof=json|text'.
+	OneofRule string = "oneof"
+
+	// RegexRule require the value to match the regular expression argument, the argument which
+	// contains ',' must be single quoted, e.g. "regex='^[a-z]{2,3}$'".
+	RegexRule string = "regex"
+)
+
+// ValidationRule is the constraint of property value, e.g. 'max=65535'.
+type ValidationRule struct {
+	Name string
+	Arg  string
+}
+
+// String return the rule as it is written in tag.
+func (r ValidationRule) String() string {
+	if r.Arg == "" {
+		return r.Name
+	}
+	return r.Name + "=" + r.Arg
+}
+
+// NewValidationRules will return the rules from the comma separated value, e.g. 'required,oneof=json|text'.
+func NewValidationRules(value string) ([]ValidationRule, error) {
+	values, err := splitRules(value)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []ValidationRule{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		rule, err := NewValidationRule(v)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// splitRules split the comma separated rules, the comma in single quoted part is kept, e.g.
+// "min=1,regex='^[a-z]{2,3}$'" is split into 'min=1' and 'regex=^[a-z]{2,3}$'. The quotes are
+// removed, and the two single quotes in quoted part is escaped as one.
+func splitRules(value string) ([]string, error) {
+	ret := []string{}
+	b := strings.Builder{}
+	quoted := false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\'' && quoted && i+1 < len(value) && value[i+1] == '\'':
+			b.WriteByte(c)
+			i++
+		case c == '\'':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			ret = append(ret, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if quoted {
+		return nil, xerrors.Errorf("Invalid rules '%v', the quote is not closed", value)
+	}
+	return append(ret, b.String()), nil
+}
+
+// NewValidationRule will return the rule from value, it must format as 'name' or 'name=arg'.
+func NewValidationRule(value string) (ValidationRule, error) {
+	name, arg, _ := strings.Cut(value, "=")
+	rule := ValidationRule{
+		Name: name,
+		Arg:  arg,
+	}
+
+	switch name {
+	case RequiredRule, NonemptyRule:
+		if arg != "" {
+			return rule, xerrors.Errorf("Invalid rule '%v', it cann't have argument", value)
+		}
+	case MinRule, MaxRule:
+		if _, err := strconv.ParseFloat(arg, 64); err != nil {
+			if _, err := time.ParseDuration(arg); err != nil {
+				return rule, xerrors.Errorf("Invalid rule '%v', the argument must be number or duration", value)
+			}
+		}
+	case OneofRule:
+		if arg == "" {
+			return rule, xerrors.Errorf("Invalid rule '%v', the argument cann't be empty", value)
+		}
+	case RegexRule:
+		if _, err := regexp.Compile(arg); err != nil {
+			return rule, xerrors.Wrapf(err, "Invalid rule '%v', the argument must be regular expression", value)
+		}
+	default:
+		return rule, xerrors.Errorf(
+			"Invalid rule '%v', it must be one of '%v', '%v', '%v', '%v', '%v' or '%v'",
+			value, RequiredRule, NonemptyRule, MinRule, MaxRule, OneofRule, RegexRule)
+	}
+	return rule, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// validateValue will check the value against rules, the required rule is not checked here
+// because it depends on the properties rather than value. The value is redacted in error if
+// it's secret.
+func validateValue(ctx context.Context, value reflect.Value, rules []ValidationRule, secret bool) error {
+	value, err := indirectPropertyValue(ctx, value)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if err := validateRule(value, rule, secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indirectPropertyValue return the underlying value of pointer and property handle.
+func indirectPropertyValue(ctx context.Context, value reflect.Value) (reflect.Value, error) {
+	handleType := reflect.TypeOf((*props.PropertyHandle)(nil)).Elem()
+	if value.Kind() != reflect.Ptr && value.CanAddr() {
+		value = value.Addr()
+	}
+	if value.Kind() == reflect.Ptr && !value.IsNil() && value.Type().Implements(handleType) {
+		if m := value.MethodByName("Get"); m.IsValid() && m.Type().NumIn() == 1 && m.Type().NumOut() == 2 {
+			res := m.Call([]reflect.Value{reflect.ValueOf(ctx)})
+			if err, _ := res[1].Interface().(error); err != nil {
+				return reflect.Value{}, err
+			}
+			value = res[0]
+		}
+	}
+
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	return value, nil
+}
+
+func validateRule(value reflect.Value, rule ValidationRule, secret bool) error {
+	switch rule.Name {
+	case NonemptyRule:
+		n, ok := valueLen(value)
+		if !ok {
+			return xerrors.Errorf("Rule '%v' cannot apply to type %v", rule, value.Type())
+		}
+		if n == 0 {
+			return xerrors.Errorf("it must be non-empty")
+		}
+	case MinRule, MaxRule:
+		return validateRange(value, rule, secret)
+	case OneofRule:
+		return eachScalar(value, func(v reflect.Value) error {
+			s := fmt.Sprint(v.Interface())
+			for _, o := range strings.Split(rule.Arg, "|") {
+				if s == o {
+					return nil
+				}
+			}
+			return xerrors.Errorf("'%v' must be one of '%v'", shownValue(s, secret), strings.ReplaceAll(rule.Arg, "|", "', '"))
+		})
+	case RegexRule:
+		re, err := regexp.Compile(rule.Arg)
+		if err != nil {
+			return err
+		}
+		return eachScalar(value, func(v reflect.Value) error {
+			s := fmt.Sprint(v.Interface())
+			if !re.MatchString(s) {
+				return xerrors.Errorf("'%v' must match '%v'", shownValue(s, secret), rule.Arg)
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// eachScalar call fn with value, or with each element if value is slice or array.
+func eachScalar(value reflect.Value, fn func(v reflect.Value) error) error {
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return fn(value)
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		if err := fn(reflect.Indirect(value.Index(i))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func valueLen(value reflect.Value) (int, bool) {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len(), true
+	default:
+		return 0, false
+	}
+}
+
+func validateRange(value reflect.Value, rule ValidationRule, secret bool) error {
+	var actual, limit float64
+	switch {
+	case value.Type() == durationType:
+		d, err := time.ParseDuration(rule.Arg)
+		if err != nil {
+			return xerrors.Errorf("Rule '%v' must have duration argument for type %v", rule, value.Type())
+		}
+		if !checkRange(rule.Name, float64(value.Int()), float64(d)) {
+			return rangeError(rule, shownValue(time.Duration(value.Int()).String(), secret))
+		}
+		return nil
+	case value.CanInt():
+		actual = float64(value.Int())
+	case value.CanUint():
+		actual = float64(value.Uint())
+	case value.CanFloat():
+		actual = value.Float()
+	default:
+		n, ok := valueLen(value)
+		if !ok {
+			return xerrors.Errorf("Rule '%v' cannot apply to type %v", rule, value.Type())
+		}
+		actual = float64(n)
+	}
+
+	limit, err := strconv.ParseFloat(rule.Arg, 64)
+	if err != nil {
+		return xerrors.Errorf("Rule '%v' must have number argument for type %v", rule, value.Type())
+	}
+	if checkRange(rule.Name, actual, limit) {
+		return nil
+	}
+
+	if _, ok := valueLen(value); ok {
+		return xerrors.Errorf("length %v must be %v", actual, rangeBound(rule))
+	}
+	return rangeError(rule, shownValue(fmt.Sprint(value.Interface()), secret))
+}
+
+func checkRange(name string, actual float64, limit float64) bool {
+	if name == MinRule {
+		return actual >= limit
+	}
+	return actual <= limit
+}
+
+func rangeBound(rule ValidationRule) string {
+	if rule.Name == MinRule {
+		return "at least " + rule.Arg
+	}
+	return "at most " + rule.Arg
+}
+
+func rangeError(rule ValidationRule, actual string) error {
+	return xerrors.Errorf("'%v' must be %v", actual, rangeBound(rule))
+}
+
+// shownValue return the value shown in error, the secret value is redacted.
+func shownValue(value string, secret bool) string {
+	if secret {
+		return props.RedactedValue
+	}
+	return value
+}
@@ -0,0 +1,336 @@
+// Copyright (c) 2025 The anyvoxel Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ioc
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/anyvoxel/airmid/ioc/props"
+)
+
+func TestNewValidationRules(t *testing.T) {
+	type testCase struct {
+		desp   string
+		value  string
+		expect []ValidationRule
+		err    string
+	}
+	testCases := []testCase{
+		{
+			desp:  "normal test",
+			value: "required, nonempty,min=1,max=10s,oneof=a|b,regex=^[a-z]+$",
+			expect: []ValidationRule{
+				{Name: RequiredRule},
+				{Name: NonemptyRule},
+				{Name: MinRule, Arg: "1"},
+				{Name: MaxRule, Arg: "10s"},
+				{Name: OneofRule, Arg: "a|b"},
+				{Name: RegexRule, Arg: "^[a-z]+$"},
+			},
+		},
+		{
+			desp:  "quoted argument",
+			value: `regex='^[a-z]{2,3}\d$',oneof='it''s|a,b'`,
+			expect: []ValidationRule{
+				{Name: RegexRule, Arg: `^[a-z]{2,3}\d$`},
+				{Name: OneofRule, Arg: "it's|a,b"},
+			},
+		},
+		{
+			desp:  "unclosed quote",
+			value: "regex='^a{2,3}$",
+			err:   "Invalid rules 'regex='\\^a\\{2,3\\}\\$', the quote is not closed",
+		},
+		{
+			desp:   "empty value",
+			value:  "",
+			expect: []ValidationRule{},
+		},
+		{
+			desp:  "unknown rule",
+			value: "lazy",
+			err:   "Invalid rule 'lazy', it must be one of",
+		},
+		{
+			desp:  "required with argument",
+			value: "required=1",
+			err:   "Invalid rule 'required=1', it cann't have argument",
+		},
+		{
+			desp:  "oneof without argument",
+			value: "oneof=",
+			err:   "Invalid rule 'oneof=', the argument cann't be empty",
+		},
+		{
+			desp:  "wrong range argument",
+			value: "max=x",
+			err:   "Invalid rule 'max=x', the argument must be number or duration",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			rules, err := NewValidationRules(tc.value)
+			if tc.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(MatchRegexp(tc.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rules).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	type testCase struct {
+		desp   string
+		value  any
+		rules  string
+		secret bool
+		err    string
+	}
+	testCases := []testCase{
+		{
+			desp:  "int in range",
+			value: 8080,
+			rules: "min=1,max=65535",
+		},
+		{
+			desp:  "int out of range",
+			value: 70000,
+			rules: "min=1,max=65535",
+			err:   "'70000' must be at most 65535",
+		},
+		{
+			desp:  "uint below min",
+			value: uint(0),
+			rules: "min=1",
+			err:   "'0' must be at least 1",
+		},
+		{
+			desp:  "float in range",
+			value: 0.5,
+			rules: "min=0,max=1",
+		},
+		{
+			desp:  "duration in range",
+			value: 5 * time.Second,
+			rules: "min=1s,max=1m",
+		},
+		{
+			desp:  "duration out of range",
+			value: 2 * time.Minute,
+			rules: "min=1s,max=1m",
+			err:   "'2m0s' must be at most 1m",
+		},
+		{
+			desp:  "duration with number argument",
+			value: time.Second,
+			rules: "max=10",
+			err:   "Rule 'max=10' must have duration argument",
+		},
+		{
+			desp:  "string length",
+			value: "abcd",
+			rules: "max=3",
+			err:   "length 4 must be at most 3",
+		},
+		{
+			desp:  "empty string",
+			value: "",
+			rules: "nonempty",
+			err:   "it must be non-empty",
+		},
+		{
+			desp:  "empty slice",
+			value: []string{},
+			rules: "nonempty",
+			err:   "it must be non-empty",
+		},
+		{
+			desp:  "nonempty on int",
+			value: 1,
+			rules: "nonempty",
+			err:   "Rule 'nonempty' cannot apply to type int",
+		},
+		{
+			desp:  "oneof",
+			value: "text",
+			rules: "oneof=json|text",
+		},
+		{
+			desp:  "not oneof",
+			value: "xml",
+			rules: "oneof=json|text",
+			err:   "'xml' must be one of 'json', 'text'",
+		},
+		{
+			desp:  "oneof of slice element",
+			value: []string{"json", "xml"},
+			rules: "oneof=json|text",
+			err:   "'xml' must be one of 'json', 'text'",
+		},
+		{
+			desp:  "regex",
+			value: "abc",
+			rules: "regex=^[a-z]+$",
+		},
+		{
+			desp:  "regex mismatch",
+			value: "ABC",
+			rules: "regex=^[a-z]+$",
+			err:   "'ABC' must match",
+		},
+		{
+			desp:   "secret out of range",
+			value:  "p",
+			rules:  "min=8",
+			secret: true,
+			err:    "length 1 must be at least 8",
+		},
+		{
+			desp:   "secret number out of range",
+			value:  1234,
+			rules:  "min=100000",
+			secret: true,
+			err:    "^'\\*\\*\\*\\*\\*\\*' must be at least 100000$",
+		},
+		{
+			desp:   "secret not oneof",
+			value:  "p1",
+			rules:  "oneof=a|b",
+			secret: true,
+			err:    "^'\\*\\*\\*\\*\\*\\*' must be one of 'a', 'b'$",
+		},
+		{
+			desp:   "secret regex mismatch",
+			value:  "p1",
+			rules:  "regex=^[a-z]+$",
+			secret: true,
+			err:    "^'\\*\\*\\*\\*\\*\\*' must match",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			rules, err := NewValidationRules(tc.rules)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = validateValue(context.Background(), reflect.ValueOf(tc.value), rules, tc.secret)
+			if tc.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(MatchRegexp(tc.err))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+type validatedBean struct {
+	Port    int                  `airmid:"value:${port:=8080},min=1,max=65535"`
+	Format  string               `airmid:"value:${format:=json}" validate:"oneof=json|text"`
+	Name    *string              `airmid:"value:${name:=}" validate:"required"`
+	Timeout *props.Value[string] `airmid:"value:${timeout:=1s}" validate:"nonempty"`
+	Level   int                  `airmid:"value:${db.secret.level:=1},max=9"`
+	Pin     int                  `airmid:"value:${pin:=1234},max=9999"`
+}
+
+func TestBeanFactoryValidateProperty(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	d, err := props.NewAESGCMDecryptor(key)
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+	props.RegisterDecryptor("validation", d)
+	t.Cleanup(func() { props.UnregisterDecryptor("validation") })
+	encryptedPin, err := props.EncryptAESGCM(key, "12345")
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	type testCase struct {
+		desp  string
+		props map[string]string
+		err   string
+	}
+	testCases := []testCase{
+		{
+			desp:  "normal test",
+			props: map[string]string{"name": "n1"},
+		},
+		{
+			desp:  "out of range",
+			props: map[string]string{"name": "n1", "port": "0"},
+			err:   "Cannot wire field 'Port' of bean 'bean1': Invalid value of key 'port': '0' must be at least 1",
+		},
+		{
+			desp:  "not oneof",
+			props: map[string]string{"name": "n1", "format": "xml"},
+			err:   "Cannot wire field 'Format' of bean 'bean1': Invalid value of key 'format': 'xml' must be one of",
+		},
+		{
+			desp:  "required",
+			props: map[string]string{},
+			err:   "Cannot wire field 'Name' of bean 'bean1': Invalid value of key 'name': it is required",
+		},
+		{
+			desp:  "value handle",
+			props: map[string]string{"name": "n1", "timeout": ""},
+			err:   "Cannot wire field 'Timeout' of bean 'bean1': Invalid value of key 'timeout': it must be non-empty",
+		},
+		{
+			desp:  "secret out of range",
+			props: map[string]string{"name": "n1", "db.secret.level": "10"},
+			err: "Cannot wire field 'Level' of bean 'bean1': Invalid value of key 'db.secret.level': " +
+				"'\\*{6}' must be at most 9",
+		},
+		{
+			desp:  "encrypted out of range",
+			props: map[string]string{"name": "n1", "pin": encryptedPin},
+			err:   "Cannot wire field 'Pin' of bean 'bean1': Invalid value of key 'pin': '\\*{6}' must be at most 9999",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desp, func(t *testing.T) {
+			g := NewWithT(t)
+			br := NewBeanFactory().(*beanFactoryImpl)
+			for k, v := range tc.props {
+				g.Expect(br.Set(context.Background(), k, v)).To(Succeed())
+			}
+			err := br.RegisterBeanDefinition("bean1", MustNewBeanDefinition(reflect.TypeOf((*validatedBean)(nil))))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			obj, err := br.GetBean(context.Background(), "bean1")
+			if tc.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(MatchRegexp(tc.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(obj.(*validatedBean).Port).To(Equal(8080))
+			g.Expect(*obj.(*validatedBean).Name).To(Equal("n1"))
+		})
+	}
+}